@@ -0,0 +1,183 @@
+package upc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AddOn represents an EAN-2 or EAN-5 supplemental code, printed to the
+// right of a UPC or EAN barcode.  EAN-2 add-ons usually carry the issue
+// number of a periodical.  EAN-5 add-ons usually carry the suggested
+// retail price of a book.
+type AddOn struct {
+	value  int
+	length int
+}
+
+var ErrAddOnLength = errors.New("add-on must be 2 or 5 digits")
+
+// ParseAddOn parses a string of 2 or 5 digits into an AddOn value.
+// The following errors can be returned in addition to integer parsing
+// errors:
+//
+//	ErrAddOnLength
+func ParseAddOn(s string) (AddOn, error) {
+	if len(s) != 2 && len(s) != 5 {
+		return AddOn{}, ErrAddOnLength
+	}
+
+	var n int
+	for _, b := range []byte(s) {
+		if b < 48 || b > 57 {
+			return AddOn{}, fmt.Errorf("Invalid add-on digit: %c", b)
+		}
+		n *= 10
+		n += int(b - 48)
+	}
+	return AddOn{value: n, length: len(s)}, nil
+}
+
+// String returns the digits of the add-on.  It's empty if there's no
+// add-on.
+func (a AddOn) String() string {
+	if a.length == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%0*d", a.length, a.value)
+}
+
+// Len returns the number of digits in the add-on: 2, 5 or 0 if there's
+// no add-on.
+func (a AddOn) Len() int {
+	return a.length
+}
+
+// Value returns the add-on as an integer.
+func (a AddOn) Value() int {
+	return a.value
+}
+
+// Issue returns the issue number carried by an EAN-2 add-on.  The
+// second return value is false for an EAN-5 add-on.
+func (a AddOn) Issue() (int, bool) {
+	return a.value, a.length == 2
+}
+
+// Price returns the suggested retail price carried by an EAN-5 add-on
+// on a book.  The first digit selects the currency (0 for pounds
+// sterling and 5 for US dollars) and the remaining 4 digits are the
+// price in pennies or cents.  The last return value is false if the
+// add-on doesn't carry a price.  See also NoPrice and IsComplimentary.
+func (a AddOn) Price() (currency string, amount int, ok bool) {
+	if a.length != 5 {
+		return "", 0, false
+	}
+	switch a.value / 10000 {
+	case 0:
+		return "GBP", a.value % 10000, true
+	case 5:
+		return "USD", a.value % 10000, true
+	default:
+		return "", 0, false
+	}
+}
+
+// NoPrice returns true if the add-on is 90000, meaning the book has
+// no suggested retail price.
+func (a AddOn) NoPrice() bool {
+	return a.length == 5 && a.value == 90000
+}
+
+// IsComplimentary returns true if the add-on is 99991, used for
+// complimentary copies of a book.
+func (a AddOn) IsComplimentary() bool {
+	return a.length == 5 && a.value == 99991
+}
+
+// UpcWithAddOn is a UPC followed by an optional add-on.
+type UpcWithAddOn struct {
+	Upc   Upc
+	AddOn AddOn
+}
+
+// String returns the UPC and add-on separated by a space.
+func (u UpcWithAddOn) String() string {
+	if u.AddOn.Len() == 0 {
+		return u.Upc.String()
+	}
+	return u.Upc.String() + " " + u.AddOn.String()
+}
+
+// EanWithAddOn is an EAN followed by an optional add-on.
+type EanWithAddOn struct {
+	Ean   Ean
+	AddOn AddOn
+}
+
+// String returns the EAN and add-on separated by a space.
+func (e EanWithAddOn) String() string {
+	if e.AddOn.Len() == 0 {
+		return e.Ean.String()
+	}
+	return e.Ean.String() + " " + e.AddOn.String()
+}
+
+// ParseWithAddOn parses a UPC followed by a 2- or 5-digit add-on, as
+// in "045496830434 12".  The add-on may be separated by a space or
+// appended directly, as scanners usually send it.  Without an add-on
+// it's equivalent to Parse.  Errors are those of Parse and ParseAddOn.
+func ParseWithAddOn(s string) (UpcWithAddOn, error) {
+	main, addOn, ok := splitAddOn(s, 12)
+	u, err := Parse(main)
+	if err != nil {
+		return UpcWithAddOn{}, err
+	}
+	if !ok {
+		return UpcWithAddOn{Upc: u}, nil
+	}
+	a, err := ParseAddOn(addOn)
+	if err != nil {
+		return UpcWithAddOn{}, err
+	}
+	return UpcWithAddOn{Upc: u, AddOn: a}, nil
+}
+
+// ParseEanWithAddOn parses an EAN followed by a 2- or 5-digit add-on.
+// The add-on may be separated by a space or appended directly, so 14
+// to 18 digits are accepted.  Without an add-on it's equivalent to
+// ParseEan.  Errors are those of ParseEan and ParseAddOn.
+func ParseEanWithAddOn(s string) (EanWithAddOn, error) {
+	main, addOn, ok := splitAddOn(s, 12, 13)
+	e, err := ParseEan(main)
+	if err != nil {
+		return EanWithAddOn{}, err
+	}
+	if !ok {
+		return EanWithAddOn{Ean: e}, nil
+	}
+	a, err := ParseAddOn(addOn)
+	if err != nil {
+		return EanWithAddOn{}, err
+	}
+	return EanWithAddOn{Ean: e, AddOn: a}, nil
+}
+
+// splitAddOn splits a string into a main code and an add-on, and
+// reports whether there was one.  Anything after a space is the
+// add-on, even if it's empty, so that the caller reports its length
+// error.  If there's no space, the add-on is found by its length: the
+// string is split when it's a valid main code length followed by 2 or
+// 5 digits.  Otherwise the whole string is returned as the main code,
+// so the caller reports the length error.
+func splitAddOn(s string, lengths ...int) (main, addOn string, ok bool) {
+	if i := strings.LastIndexByte(s, ' '); i >= 0 {
+		return s[:i], s[i+1:], true
+	}
+	for _, n := range lengths {
+		if len(s) == n+2 || len(s) == n+5 {
+			return s[:n], s[n:], true
+		}
+	}
+	return s, "", false
+}
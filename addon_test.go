@@ -0,0 +1,170 @@
+package upc
+
+import "testing"
+
+// a breakdown of an add-on into each of its possible attributes
+type addOnBreakdown struct {
+	issue         int
+	isIssue       bool
+	currency      string
+	amount        int
+	isPrice       bool
+	noPrice       bool
+	complimentary bool
+}
+
+var addOnTests = map[string]addOnBreakdown{
+	"12": {
+		issue:   12,
+		isIssue: true,
+	},
+	"05": {
+		issue:   5,
+		isIssue: true,
+	},
+	"52495": {
+		currency: "USD",
+		amount:   2495,
+		isPrice:  true,
+	},
+	"01299": {
+		currency: "GBP",
+		amount:   1299,
+		isPrice:  true,
+	},
+	"90000": {
+		noPrice: true,
+	},
+	"99991": {
+		complimentary: true,
+	},
+	"12345": {},
+}
+
+func TestAddOn(t *testing.T) {
+	for s, expect := range addOnTests {
+		a, err := ParseAddOn(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		var got addOnBreakdown
+		got.issue, got.isIssue = a.Issue()
+		if !got.isIssue {
+			got.issue = 0
+		}
+		got.currency, got.amount, got.isPrice = a.Price()
+		got.noPrice = a.NoPrice()
+		got.complimentary = a.IsComplimentary()
+		if got != expect {
+			t.Errorf("%s: wrong breakdown\n got: %#v\nwant: %#v\n", s, got, expect)
+		}
+		if a.String() != s || a.Len() != len(s) {
+			t.Errorf("%s: wrong string: got %s", s, a)
+		}
+	}
+
+	for _, s := range []string{"", "1", "123", "123456"} {
+		if _, err := ParseAddOn(s); err != ErrAddOnLength {
+			t.Errorf("%s: expected ErrAddOnLength got %q", s, err)
+		}
+	}
+	if _, err := ParseAddOn("1x"); err == nil {
+		t.Errorf("expected an error got none")
+	}
+}
+
+func TestParseWithAddOn(t *testing.T) {
+	tests := map[string]string{
+		"045496830434":       "045496830434",
+		"045496830434 12":    "045496830434 12",
+		"04549683043412":     "045496830434 12",
+		"04549683043452495":  "045496830434 52495",
+		"045496830434 52495": "045496830434 52495",
+	}
+	for s, want := range tests {
+		u, err := ParseWithAddOn(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if u.String() != want {
+			t.Errorf("%s: got %s want %s", s, u, want)
+		}
+	}
+
+	errs := map[string]error{
+		"04549683043":         ErrTooShort,
+		"0454968304341":       ErrTooLong,
+		"045496830435 12":     ErrInvalidCheckDigit,
+		"045496830434 123":    ErrAddOnLength,
+		"045496830434 1":      ErrAddOnLength,
+		"045496830434 ":       ErrAddOnLength,
+		"045496830434 \t":     ErrAddOnLength,
+		"0045496830434 52495": ErrTooLong,
+	}
+	for s, want := range errs {
+		if _, err := ParseWithAddOn(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+}
+
+func TestParseAddOnHint(t *testing.T) {
+	// Parse and ParseEan point to the add-on parsers for codes which
+	// they would accept
+	upcs := map[string]error{
+		"045496830434 12":    ErrAddOn,
+		"04549683043452495":  ErrAddOn,
+		"045496830435 12":    ErrTooLong,
+		"045496830434 123":   ErrTooLong,
+		"0045496830434 1234": ErrTooLong,
+	}
+	for s, want := range upcs {
+		if _, err := Parse(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+	eans := map[string]error{
+		"9780306406157 52495": ErrEanAddOn,
+		"978030640615752495":  ErrEanAddOn,
+		"04549683043412":      ErrEanAddOn,
+		"9780306406158 52495": ErrEanTooLong,
+	}
+	for s, want := range eans {
+		if _, err := ParseEan(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+}
+
+func TestParseEanWithAddOn(t *testing.T) {
+	tests := map[string]string{
+		"9780306406157":       "9780306406157",
+		"045496830434":        "0045496830434",
+		"978030640615752495":  "9780306406157 52495",
+		"9780306406157 52495": "9780306406157 52495",
+		"977031784700105":     "9770317847001 05",
+		"04549683043412":      "0045496830434 12",
+		"04549683043490000":   "0045496830434 90000",
+	}
+	for s, want := range tests {
+		e, err := ParseEanWithAddOn(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if e.String() != want {
+			t.Errorf("%s: got %s want %s", s, e, want)
+		}
+	}
+
+	errs := map[string]error{
+		"97803064061":          ErrEanTooShort,
+		"9780306406157123":     ErrEanTooLong,
+		"9780306406158 52495":  ErrEanInvalidCheckDigit,
+		"9780306406157 524951": ErrAddOnLength,
+		"9780306406157 ":       ErrAddOnLength,
+	}
+	for s, want := range errs {
+		if _, err := ParseEanWithAddOn(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+}
@@ -0,0 +1,774 @@
+package upc
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Elements holds the data of a GS1 element string, as found in
+// GS1-128, GS1 DataBar, GS1 DataMatrix and GS1 QR Code symbols, keyed
+// by application identifier.  Values are typed according to the AI:
+//
+//	Sscc            00
+//	Gtin            01, 02, 03
+//	AIDate          dates such as 11, 15 and 17, and 7003
+//	Decimal         measures such as 310n and amounts such as 390n
+//	CurrencyAmount  amounts with a currency, 391n and 393n
+//	int             counts, 30 and 37
+//	string          everything else
+//
+// Use Data to get the data of any element as it appears in the element
+// string.
+type Elements map[string]interface{}
+
+// Decimal is a numeric value with implied decimal places, as carried
+// by AIs whose last digit gives the position of the decimal point.
+type Decimal struct {
+	Value  int64
+	Places int
+}
+
+// Float64 returns the decimal as a floating point number.
+func (d Decimal) Float64() float64 {
+	return float64(d.Value) / float64(pow10(d.Places))
+}
+
+// String returns the decimal with its decimal point, e.g. 1.250.
+func (d Decimal) String() string {
+	if d.Places == 0 {
+		return strconv.FormatInt(d.Value, 10)
+	}
+	s := fmt.Sprintf("%0*d", d.Places+1, d.Value)
+	return s[:len(s)-d.Places] + "." + s[len(s)-d.Places:]
+}
+
+// CurrencyAmount is an amount in a currency identified by its ISO 4217
+// numeric code, e.g. 840 for US dollars.
+type CurrencyAmount struct {
+	Currency string
+	Amount   Decimal
+}
+
+// AIDate is the date of an element, with the time of day for AIs such
+// as 7003.  Parsed dates are in UTC.  A day of 00 in the element string
+// stands for the last day of the month: Time is that day and
+// LastDayOfMonth is set, so that the date is written with a day of 00
+// again.  The day of Time is ignored when LastDayOfMonth is set.
+type AIDate struct {
+	Time           time.Time
+	LastDayOfMonth bool
+}
+
+var ErrElementSyntax = errors.New("malformed GS1 element string")
+var ErrUnknownAI = errors.New("unknown GS1 application identifier")
+var ErrAIDataLength = errors.New("GS1 element data has the wrong length")
+var ErrAIData = errors.New("GS1 element data has an invalid character")
+var ErrAICheckDigit = errors.New("GS1 element data has an invalid check digit")
+var ErrAIDate = errors.New("GS1 element data has an invalid date")
+var ErrAIDuplicate = errors.New("GS1 element string repeats an application identifier with different data")
+
+// groupSeparator is the ASCII character which stands for FNC1 between
+// elements in the data sent by scanners.
+const groupSeparator = '\x1d'
+
+// ParseElements parses a GS1 element string.  It accepts the human
+// readable form with application identifiers in parentheses, as in
+// "(01)00045496830434(17)251231(10)ABC", and the raw form sent by
+// scanners, where a variable-length element is terminated by an ASCII
+// group separator (FNC1).  The raw form may begin with a symbology
+// identifier such as "]C1".  The following errors can be returned:
+//
+//	ErrElementSyntax
+//	ErrUnknownAI
+//	ErrAIDataLength
+//	ErrAIData
+//	ErrAICheckDigit
+//	ErrAIDate
+//	ErrAIDuplicate
+func ParseElements(s string) (Elements, error) {
+	if strings.HasPrefix(s, "(") {
+		return parseBracketed(s)
+	}
+	return parseRaw(s)
+}
+
+// parseBracketed parses the human readable form of an element string.
+func parseBracketed(s string) (Elements, error) {
+	e := make(Elements)
+	for len(s) > 0 {
+		end := strings.IndexByte(s, ')')
+		if s[0] != '(' || end < 0 {
+			return nil, ErrElementSyntax
+		}
+		ai := s[1:end]
+		spec, ok := lookupAI(ai)
+		if !ok || spec.ai != ai {
+			return nil, ErrUnknownAI
+		}
+		s = s[end+1:]
+
+		// Data may contain parentheses, so it ends at the next
+		// known AI in parentheses.
+		n := len(s)
+		for i := 0; i < len(s); i++ {
+			if s[i] == '(' && nextBracketedAI(s[i:]) {
+				n = i
+				break
+			}
+		}
+		if err := e.add(spec, s[:n]); err != nil {
+			return nil, err
+		}
+		s = s[n:]
+	}
+	if len(e) == 0 {
+		return nil, ErrElementSyntax
+	}
+	return e, nil
+}
+
+// nextBracketedAI returns true if s begins with a known AI in
+// parentheses.
+func nextBracketedAI(s string) bool {
+	end := strings.IndexByte(s, ')')
+	if end < 3 || end > 5 {
+		return false
+	}
+	spec, ok := lookupAI(s[1:end])
+	return ok && spec.ai == s[1:end]
+}
+
+// parseRaw parses the form of an element string sent by scanners.
+func parseRaw(s string) (Elements, error) {
+	for _, id := range []string{"]C1", "]e0", "]d2", "]Q3", "]J1"} {
+		s = strings.TrimPrefix(s, id)
+	}
+	s = strings.TrimLeft(s, string(groupSeparator))
+
+	e := make(Elements)
+	for len(s) > 0 {
+		spec, ok := lookupAI(s)
+		if !ok {
+			return nil, ErrUnknownAI
+		}
+		s = s[len(spec.ai):]
+
+		var data string
+		if n := predefinedLength(spec.ai); n > 0 {
+			n -= len(spec.ai)
+			if len(s) < n {
+				return nil, ErrAIDataLength
+			}
+			data, s = s[:n], s[n:]
+		} else if end := strings.IndexByte(s, groupSeparator); end >= 0 {
+			data, s = s[:end], s[end:]
+		} else {
+			data, s = s, ""
+		}
+		if err := e.add(spec, data); err != nil {
+			return nil, err
+		}
+		// encoders sometimes terminate predefined lengths too
+		s = strings.TrimPrefix(s, string(groupSeparator))
+	}
+	if len(e) == 0 {
+		return nil, ErrElementSyntax
+	}
+	return e, nil
+}
+
+// add validates the data of an element and stores its typed value.
+func (e Elements) add(spec *aiSpec, data string) error {
+	v, err := spec.parse(data)
+	if err != nil {
+		return err
+	}
+	return e.put(spec.ai, v)
+}
+
+// put stores the typed value of an element, unless the element is
+// already present with a different value.
+func (e Elements) put(ai string, v interface{}) error {
+	if old, ok := e[ai]; ok && old != v {
+		return ErrAIDuplicate
+	}
+	e[ai] = v
+	return nil
+}
+
+// Data returns the data of an element as it appears in an element
+// string.  The second return value is false if the element is absent
+// or its value doesn't have the type the AI requires.
+func (e Elements) Data(ai string) (string, bool) {
+	v, ok := e[ai]
+	if !ok {
+		return "", false
+	}
+	spec, ok := lookupAI(ai)
+	if !ok || spec.ai != ai {
+		return "", false
+	}
+	d, err := spec.format(v)
+	if err != nil {
+		return "", false
+	}
+	return d, true
+}
+
+// Gtin returns the GTIN of the trade item, AI 01, or the GTIN of the
+// contained trade items, AI 02.
+func (e Elements) Gtin() (Gtin, bool) {
+	for _, ai := range []string{"01", "02"} {
+		if g, ok := e[ai].(Gtin); ok {
+			return g, true
+		}
+	}
+	return Gtin{}, false
+}
+
+// predefinedLength returns the total length, including the AI, of an
+// element whose length is predefined by the first two digits of its
+// AI.  These elements don't need an FNC1 separator.  It returns 0 for
+// all other elements.
+func predefinedLength(ai string) int {
+	switch ai[:2] {
+	case "00":
+		return 20
+	case "01", "02", "03", "41":
+		return 16
+	case "04":
+		return 18
+	case "11", "12", "13", "14", "15", "16", "17", "18", "19":
+		return 8
+	case "20":
+		return 4
+	case "31", "32", "33", "34", "35", "36":
+		return 10
+	}
+	return 0
+}
+
+// aiKind is the type of the value of an element.
+type aiKind int
+
+const (
+	kindString aiKind = iota
+	kindSscc
+	kindGtin
+	kindDate
+	kindDateTime
+	kindDecimal
+	kindCurrency
+	kindInt
+)
+
+// aiSpec describes the data of an application identifier.  The syntax
+// follows the GS1 General Specifications: components separated by "+",
+// each N (digits), X (CSET 82), Y (CSET 39), Z (CSET 64) or - (a minus
+// sign) followed by a fixed length, a maximum length such as "..20" or
+// a range such as "6..12" or "0..1".
+type aiSpec struct {
+	ai     string
+	title  string
+	syntax string
+	check  bool // the first component ends with a mod-10 check digit
+	kind   aiKind
+}
+
+// component is one part of the format of an element.
+type component struct {
+	charset  byte
+	min, max int
+}
+
+// components parses the format of an element.
+func (spec *aiSpec) components() []component {
+	var cs []component
+	for _, f := range strings.Split(spec.syntax, "+") {
+		c := component{charset: f[0]}
+		if i := strings.Index(f, ".."); i >= 0 {
+			c.min, _ = strconv.Atoi(f[1:i])
+			c.max, _ = strconv.Atoi(f[i+2:])
+			if i == 1 {
+				c.min = 1
+			}
+		} else {
+			c.min, _ = strconv.Atoi(f[1:])
+			c.max = c.min
+		}
+		cs = append(cs, c)
+	}
+	return cs
+}
+
+// validate checks the length, characters and check digit of the data
+// of an element.
+func (spec *aiSpec) validate(data string) error {
+	cs := spec.components()
+	for i, c := range cs {
+		n := c.max
+		if i == len(cs)-1 {
+			if len(data) < c.min || len(data) > c.max {
+				return ErrAIDataLength
+			}
+			n = len(data)
+		} else if len(data) < n {
+			return ErrAIDataLength
+		}
+		part := data[:n]
+		data = data[n:]
+
+		for _, b := range []byte(part) {
+			if !inCharset(c.charset, b) {
+				return ErrAIData
+			}
+		}
+		if i == 0 && spec.check && Ean(atoi64(part[:len(part)-1])).CheckDigit() != int(part[len(part)-1]-48) {
+			return ErrAICheckDigit
+		}
+	}
+	return nil
+}
+
+// inCharset returns true if b belongs to a GS1 character set: digits
+// (N), the 82 characters of CSET 82 (X), the 39 of CSET 39 (Y) or the
+// 64 of CSET 64 (Z), the URL-safe base64 alphabet, with its = padding.
+// A minus sign (-) is a set of its own.
+func inCharset(charset, b byte) bool {
+	switch {
+	case charset == '-':
+		return b == '-'
+	case charset == 'N':
+		return b >= '0' && b <= '9'
+	case b >= '0' && b <= '9', b >= 'A' && b <= 'Z':
+		return true
+	case charset == 'Y':
+		return b == '#' || b == '-' || b == '/'
+	case b >= 'a' && b <= 'z':
+		return true
+	case charset == 'Z':
+		return b == '-' || b == '_' || b == '='
+	default:
+		return strings.IndexByte("!\"%&'()*+,-./:;<=>?_", b) >= 0
+	}
+}
+
+// atoi64 converts a string of digits to an integer.
+func atoi64(s string) int64 {
+	var n int64
+	for _, b := range []byte(s) {
+		n *= 10
+		n += int64(b - 48)
+	}
+	return n
+}
+
+// parse validates the data of an element and converts it to the typed
+// value for the AI.
+func (spec *aiSpec) parse(data string) (interface{}, error) {
+	if err := spec.validate(data); err != nil {
+		return nil, err
+	}
+
+	switch spec.kind {
+	case kindSscc:
+		return ParseSscc(data)
+	case kindGtin:
+		return ParseGtin(data)
+	case kindDate, kindDateTime:
+		return parseAIDate(data, timeNow())
+	case kindDecimal:
+		return Decimal{atoi64(data), int(spec.ai[3] - 48)}, nil
+	case kindCurrency:
+		return CurrencyAmount{data[:3], Decimal{atoi64(data[3:]), int(spec.ai[3] - 48)}}, nil
+	case kindInt:
+		return int(atoi64(data)), nil
+	default:
+		return data, nil
+	}
+}
+
+// format converts a typed value back to the data of an element.
+func (spec *aiSpec) format(v interface{}) (string, error) {
+	var data string
+	switch spec.kind {
+	case kindSscc:
+		c, ok := v.(Sscc)
+		if !ok {
+			return "", fmt.Errorf("AI (%s) requires an Sscc, got %T", spec.ai, v)
+		}
+		data = c.String()
+	case kindGtin:
+		g, ok := v.(Gtin)
+		if !ok {
+			return "", fmt.Errorf("AI (%s) requires a Gtin, got %T", spec.ai, v)
+		}
+		data = g.Gtin14()
+	case kindDate:
+		d, ok := v.(AIDate)
+		if !ok {
+			return "", fmt.Errorf("AI (%s) requires an AIDate, got %T", spec.ai, v)
+		}
+		data = d.format("060102")
+	case kindDateTime:
+		d, ok := v.(AIDate)
+		if !ok {
+			return "", fmt.Errorf("AI (%s) requires an AIDate, got %T", spec.ai, v)
+		}
+		data = d.format("0601021504")
+	case kindDecimal:
+		d, ok := v.(Decimal)
+		if !ok || d.Places != int(spec.ai[3]-48) {
+			return "", fmt.Errorf("AI (%s) requires a Decimal with %c places, got %v", spec.ai, spec.ai[3], v)
+		}
+		data = spec.pad(d.Value)
+	case kindCurrency:
+		c, ok := v.(CurrencyAmount)
+		if !ok || c.Amount.Places != int(spec.ai[3]-48) {
+			return "", fmt.Errorf("AI (%s) requires a CurrencyAmount with %c places, got %v", spec.ai, spec.ai[3], v)
+		}
+		data = c.Currency + strconv.FormatInt(c.Amount.Value, 10)
+	case kindInt:
+		n, ok := v.(int)
+		if !ok {
+			return "", fmt.Errorf("AI (%s) requires an int, got %T", spec.ai, v)
+		}
+		data = spec.pad(int64(n))
+	default:
+		s, ok := v.(string)
+		if !ok {
+			return "", fmt.Errorf("AI (%s) requires a string, got %T", spec.ai, v)
+		}
+		data = s
+	}
+	if err := spec.validate(data); err != nil {
+		return "", err
+	}
+	return data, nil
+}
+
+// pad formats a number for a purely numeric element, padded with
+// zeros if the length is fixed.
+func (spec *aiSpec) pad(n int64) string {
+	cs := spec.components()
+	if len(cs) == 1 && cs[0].min == cs[0].max {
+		return fmt.Sprintf("%0*d", cs[0].max, n)
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// timeNow returns the current time.  Tests replace it.
+var timeNow = time.Now
+
+// parseAIDate parses a GS1 date, YYMMDD, optionally followed by HHMM.
+// The century is chosen so the year is at most 49 years in the past
+// or 50 years in the future of now.  A day of 00 stands for the last
+// day of the month.
+func parseAIDate(s string, now time.Time) (AIDate, error) {
+	yy := int(atoi64(s[0:2]))
+	month := time.Month(atoi64(s[2:4]))
+	day := int(atoi64(s[4:6]))
+	var hour, min int
+	if len(s) == 10 {
+		hour, min = int(atoi64(s[6:8])), int(atoi64(s[8:10]))
+		if hour > 23 || min > 59 {
+			return AIDate{}, ErrAIDate
+		}
+	}
+
+	year := now.Year()/100*100 + yy
+	switch diff := year - now.Year(); {
+	case diff >= 51:
+		year -= 100
+	case diff <= -50:
+		year += 100
+	}
+
+	if month < 1 || month > 12 {
+		return AIDate{}, ErrAIDate
+	}
+	if day == 0 {
+		// the day before the first of next month
+		return AIDate{time.Date(year, month+1, 0, hour, min, 0, 0, time.UTC), true}, nil
+	}
+	t := time.Date(year, month, day, hour, min, 0, 0, time.UTC)
+	if t.Day() != day {
+		return AIDate{}, ErrAIDate
+	}
+	return AIDate{Time: t}, nil
+}
+
+// format formats the date with the layout, writing a day of 00 for
+// the last day of the month.
+func (d AIDate) format(layout string) string {
+	s := d.Time.Format(layout)
+	if d.LastDayOfMonth {
+		s = s[:4] + "00" + s[6:]
+	}
+	return s
+}
+
+// lookupAI finds the application identifier at the beginning of s.
+// Application identifiers are prefix-free, so there's at most one.
+func lookupAI(s string) (*aiSpec, bool) {
+	for n := 2; n <= 4 && n <= len(s); n++ {
+		if spec, ok := aiTable[s[:n]]; ok {
+			return spec, true
+		}
+	}
+	return nil, false
+}
+
+// AITitle returns the data title of an application identifier, such
+// as "BATCH/LOT" for 10.  It's empty if the AI is unknown.
+func AITitle(ai string) string {
+	if spec, ok := lookupAI(ai); ok && spec.ai == ai {
+		return spec.title
+	}
+	return ""
+}
+
+// aiTable holds every application identifier, by AI.
+var aiTable = make(map[string]*aiSpec)
+
+func init() {
+	for i := range aiSpecs {
+		aiTable[aiSpecs[i].ai] = &aiSpecs[i]
+	}
+
+	// AIs whose last digit is the number of decimal places
+	for _, d := range decimalSpecs {
+		for n := 0; n <= d.places; n++ {
+			ai := fmt.Sprintf("%s%d", d.prefix, n)
+			aiTable[ai] = &aiSpec{ai: ai, title: d.title, syntax: d.syntax, kind: d.kind}
+		}
+	}
+
+	// AIs whose last digit is a sequence number
+	for n := 0; n <= 9; n++ {
+		ai := fmt.Sprintf("703%d", n)
+		aiTable[ai] = &aiSpec{ai: ai, title: fmt.Sprintf("PROCESSOR # %d", n), syntax: "N3+X..27"}
+	}
+	for n := 0; n <= 9; n++ {
+		ai := fmt.Sprintf("723%d", n)
+		aiTable[ai] = &aiSpec{ai: ai, title: fmt.Sprintf("CERT # %d", n+1), syntax: "X2+X..28"}
+	}
+	for n := 91; n <= 99; n++ {
+		ai := strconv.Itoa(n)
+		aiTable[ai] = &aiSpec{ai: ai, title: "INTERNAL", syntax: "X..90"}
+	}
+}
+
+var aiSpecs = []aiSpec{
+	{"00", "SSCC", "N18", true, kindSscc},
+	{"01", "GTIN", "N14", true, kindGtin},
+	{"02", "CONTENT", "N14", true, kindGtin},
+	{"03", "MTO GTIN", "N14", true, kindGtin},
+	{"10", "BATCH/LOT", "X..20", false, kindString},
+	{"11", "PROD DATE", "N6", false, kindDate},
+	{"12", "DUE DATE", "N6", false, kindDate},
+	{"13", "PACK DATE", "N6", false, kindDate},
+	{"15", "BEST BEFORE or BEST BY", "N6", false, kindDate},
+	{"16", "SELL BY", "N6", false, kindDate},
+	{"17", "USE BY or EXPIRY", "N6", false, kindDate},
+	{"20", "VARIANT", "N2", false, kindString},
+	{"21", "SERIAL", "X..20", false, kindString},
+	{"22", "CPV", "X..20", false, kindString},
+	{"235", "TPX", "X..28", false, kindString},
+	{"240", "ADDITIONAL ID", "X..30", false, kindString},
+	{"241", "CUST. PART No.", "X..30", false, kindString},
+	{"242", "MTO VARIANT", "N..6", false, kindString},
+	{"243", "PCN", "X..20", false, kindString},
+	{"250", "SECONDARY SERIAL", "X..30", false, kindString},
+	{"251", "REF. TO SOURCE", "X..30", false, kindString},
+	{"253", "GDTI", "N13+X..17", true, kindString},
+	{"254", "GLN EXTENSION COMPONENT", "X..20", false, kindString},
+	{"255", "GCN", "N13+N..12", true, kindString},
+	{"30", "VAR. COUNT", "N..8", false, kindInt},
+	{"37", "COUNT", "N..8", false, kindInt},
+	{"400", "ORDER NUMBER", "X..30", false, kindString},
+	{"401", "GINC", "X..30", false, kindString},
+	{"402", "GSIN", "N17", true, kindString},
+	{"403", "ROUTE", "X..30", false, kindString},
+	{"410", "SHIP TO LOC", "N13", true, kindString},
+	{"411", "BILL TO", "N13", true, kindString},
+	{"412", "PURCHASE FROM", "N13", true, kindString},
+	{"413", "SHIP FOR LOC", "N13", true, kindString},
+	{"414", "LOC No.", "N13", true, kindString},
+	{"415", "PAY TO", "N13", true, kindString},
+	{"416", "PROD/SERV LOC", "N13", true, kindString},
+	{"417", "PARTY", "N13", true, kindString},
+	{"420", "SHIP TO POST", "X..20", false, kindString},
+	{"421", "SHIP TO POST", "N3+X..9", false, kindString},
+	{"422", "ORIGIN", "N3", false, kindString},
+	{"423", "COUNTRY - INITIAL PROCESS.", "N3+N..12", false, kindString},
+	{"424", "COUNTRY - PROCESS.", "N3", false, kindString},
+	{"425", "COUNTRY - DISASSEMBLY", "N3+N..12", false, kindString},
+	{"426", "COUNTRY - FULL PROCESS", "N3", false, kindString},
+	{"427", "ORIGIN SUBDIVISION", "X..3", false, kindString},
+	{"4300", "SHIP TO COMP", "X..35", false, kindString},
+	{"4301", "SHIP TO NAME", "X..35", false, kindString},
+	{"4302", "SHIP TO ADD1", "X..70", false, kindString},
+	{"4303", "SHIP TO ADD2", "X..70", false, kindString},
+	{"4304", "SHIP TO SUB", "X..70", false, kindString},
+	{"4305", "SHIP TO LOC", "X..70", false, kindString},
+	{"4306", "SHIP TO REG", "X..70", false, kindString},
+	{"4307", "SHIP TO COUNTRY", "X2", false, kindString},
+	{"4308", "SHIP TO PHONE", "X..30", false, kindString},
+	{"4309", "SHIP TO GEO", "N20", false, kindString},
+	{"4310", "RTN TO COMP", "X..35", false, kindString},
+	{"4311", "RTN TO NAME", "X..35", false, kindString},
+	{"4312", "RTN TO ADD1", "X..70", false, kindString},
+	{"4313", "RTN TO ADD2", "X..70", false, kindString},
+	{"4314", "RTN TO SUB", "X..70", false, kindString},
+	{"4315", "RTN TO LOC", "X..70", false, kindString},
+	{"4316", "RTN TO REG", "X..70", false, kindString},
+	{"4317", "RTN TO COUNTRY", "X2", false, kindString},
+	{"4318", "RTN TO POST", "X..20", false, kindString},
+	{"4319", "RTN TO PHONE", "X..30", false, kindString},
+	{"4320", "SRV DESCRIPTION", "X..35", false, kindString},
+	{"4321", "DANGEROUS GOODS", "N1", false, kindString},
+	{"4322", "AUTH LEAVE", "N1", false, kindString},
+	{"4323", "SIG REQUIRED", "N1", false, kindString},
+	{"4324", "NBEF DEL DT", "N10", false, kindDateTime},
+	{"4325", "NAFT DEL DT", "N10", false, kindDateTime},
+	{"4326", "REL DATE", "N6", false, kindDate},
+	{"4330", "MAX TEMP F.", "N6+-0..1", false, kindString},
+	{"4331", "MAX TEMP C.", "N6+-0..1", false, kindString},
+	{"4332", "MIN TEMP F.", "N6+-0..1", false, kindString},
+	{"4333", "MIN TEMP C.", "N6+-0..1", false, kindString},
+	{"7001", "NSN", "N13", false, kindString},
+	{"7002", "MEAT CUT", "X..30", false, kindString},
+	{"7003", "EXPIRY TIME", "N10", false, kindDateTime},
+	{"7004", "ACTIVE POTENCY", "N..4", false, kindString},
+	{"7005", "CATCH AREA", "X..12", false, kindString},
+	{"7006", "FIRST FREEZE DATE", "N6", false, kindDate},
+	{"7007", "HARVEST DATE", "N6..12", false, kindString},
+	{"7008", "AQUATIC SPECIES", "X..3", false, kindString},
+	{"7009", "FISHING GEAR TYPE", "X..10", false, kindString},
+	{"7010", "PROD METHOD", "X..2", false, kindString},
+	{"7011", "TEST BY DATE", "N6+N..4", false, kindString},
+	{"7020", "REFURB LOT", "X..20", false, kindString},
+	{"7021", "FUNC STAT", "X..20", false, kindString},
+	{"7022", "REV STAT", "X..20", false, kindString},
+	{"7023", "GIAI - ASSEMBLY", "X..30", false, kindString},
+	{"7040", "UIC+EXT", "N1+X3", false, kindString},
+	{"7041", "UFRGT UNIT TYPE", "X..4", false, kindString},
+	{"710", "NHRN PZN", "X..20", false, kindString},
+	{"711", "NHRN CIP", "X..20", false, kindString},
+	{"712", "NHRN CN", "X..20", false, kindString},
+	{"713", "NHRN DRN", "X..20", false, kindString},
+	{"714", "NHRN AIM", "X..20", false, kindString},
+	{"715", "NHRN NDC", "X..20", false, kindString},
+	{"7240", "PROTOCOL", "X..20", false, kindString},
+	{"7241", "AIDC MEDIA TYPE", "N2", false, kindString},
+	{"7242", "VCN", "X..25", false, kindString},
+	{"7250", "DOB", "N8", false, kindString},
+	{"7251", "DOB TIME", "N12", false, kindString},
+	{"7252", "BIO SEX", "N1", false, kindString},
+	{"7253", "FAMILY NAME", "X..40", false, kindString},
+	{"7254", "GIVEN NAME", "X..40", false, kindString},
+	{"7255", "SUFFIX", "X..10", false, kindString},
+	{"7256", "FULL NAME", "X..90", false, kindString},
+	{"7257", "PERSON ADDR", "X..70", false, kindString},
+	{"7258", "BIRTH SEQUENCE", "N1+X1+N1", false, kindString},
+	{"7259", "BABY", "X..40", false, kindString},
+	{"8001", "DIMENSIONS", "N14", false, kindString},
+	{"8002", "CMT No.", "X..20", false, kindString},
+	{"8003", "GRAI", "N14+X..16", true, kindString},
+	{"8004", "GIAI", "X..30", false, kindString},
+	{"8005", "PRICE PER UNIT", "N6", false, kindString},
+	{"8006", "ITIP", "N14+N2+N2", true, kindString},
+	{"8007", "IBAN", "X..34", false, kindString},
+	{"8008", "PROD TIME", "N8+N..4", false, kindString},
+	{"8009", "OPTSEN", "X..50", false, kindString},
+	{"8010", "CPID", "Y..30", false, kindString},
+	{"8011", "CPID SERIAL", "N..12", false, kindString},
+	{"8012", "VERSION", "X..20", false, kindString},
+	{"8013", "GMN", "X..25", false, kindString},
+	{"8014", "MUDI", "X..25", false, kindString},
+	{"8017", "GSRN - PROVIDER", "N18", true, kindString},
+	{"8018", "GSRN - RECIPIENT", "N18", true, kindString},
+	{"8019", "SRIN", "N..10", false, kindString},
+	{"8020", "REF No.", "X..25", false, kindString},
+	{"8026", "ITIP CONTENT", "N14+N2+N2", true, kindString},
+	{"8030", "DIGSIG", "Z..90", false, kindString},
+	{"8110", "COUPON", "X..70", false, kindString},
+	{"8111", "POINTS", "N4", false, kindString},
+	{"8112", "PAPERLESS COUPON", "X..70", false, kindString},
+	{"8200", "PRODUCT URL", "X..70", false, kindString},
+	{"90", "INTERNAL", "X..30", false, kindString},
+}
+
+// decimalSpecs describes the AIs whose last digit, from 0 to places,
+// is the number of decimal places of the value.
+var decimalSpecs = []struct {
+	prefix string
+	title  string
+	syntax string
+	kind   aiKind
+	places int
+}{
+	{"310", "NET WEIGHT (kg)", "N6", kindDecimal, 5},
+	{"311", "LENGTH (m)", "N6", kindDecimal, 5},
+	{"312", "WIDTH (m)", "N6", kindDecimal, 5},
+	{"313", "HEIGHT (m)", "N6", kindDecimal, 5},
+	{"314", "AREA (m²)", "N6", kindDecimal, 5},
+	{"315", "NET VOLUME (l)", "N6", kindDecimal, 5},
+	{"316", "NET VOLUME (m³)", "N6", kindDecimal, 5},
+	{"320", "NET WEIGHT (lb)", "N6", kindDecimal, 5},
+	{"321", "LENGTH (in)", "N6", kindDecimal, 5},
+	{"322", "LENGTH (ft)", "N6", kindDecimal, 5},
+	{"323", "LENGTH (yd)", "N6", kindDecimal, 5},
+	{"324", "WIDTH (in)", "N6", kindDecimal, 5},
+	{"325", "WIDTH (ft)", "N6", kindDecimal, 5},
+	{"326", "WIDTH (yd)", "N6", kindDecimal, 5},
+	{"327", "HEIGHT (in)", "N6", kindDecimal, 5},
+	{"328", "HEIGHT (ft)", "N6", kindDecimal, 5},
+	{"329", "HEIGHT (yd)", "N6", kindDecimal, 5},
+	{"330", "GROSS WEIGHT (kg)", "N6", kindDecimal, 5},
+	{"331", "LENGTH (m), log", "N6", kindDecimal, 5},
+	{"332", "WIDTH (m), log", "N6", kindDecimal, 5},
+	{"333", "HEIGHT (m), log", "N6", kindDecimal, 5},
+	{"334", "AREA (m²), log", "N6", kindDecimal, 5},
+	{"335", "VOLUME (l), log", "N6", kindDecimal, 5},
+	{"336", "VOLUME (m³), log", "N6", kindDecimal, 5},
+	{"337", "KG PER m²", "N6", kindDecimal, 5},
+	{"340", "GROSS WEIGHT (lb)", "N6", kindDecimal, 5},
+	{"341", "LENGTH (in), log", "N6", kindDecimal, 5},
+	{"342", "LENGTH (ft), log", "N6", kindDecimal, 5},
+	{"343", "LENGTH (yd), log", "N6", kindDecimal, 5},
+	{"344", "WIDTH (in), log", "N6", kindDecimal, 5},
+	{"345", "WIDTH (ft), log", "N6", kindDecimal, 5},
+	{"346", "WIDTH (yd), log", "N6", kindDecimal, 5},
+	{"347", "HEIGHT (in), log", "N6", kindDecimal, 5},
+	{"348", "HEIGHT (ft), log", "N6", kindDecimal, 5},
+	{"349", "HEIGHT (yd), log", "N6", kindDecimal, 5},
+	{"350", "AREA (in²)", "N6", kindDecimal, 5},
+	{"351", "AREA (ft²)", "N6", kindDecimal, 5},
+	{"352", "AREA (yd²)", "N6", kindDecimal, 5},
+	{"353", "AREA (in²), log", "N6", kindDecimal, 5},
+	{"354", "AREA (ft²), log", "N6", kindDecimal, 5},
+	{"355", "AREA (yd²), log", "N6", kindDecimal, 5},
+	{"356", "NET WEIGHT (troy oz)", "N6", kindDecimal, 5},
+	{"357", "NET VOLUME (oz)", "N6", kindDecimal, 5},
+	{"360", "NET VOLUME (qt)", "N6", kindDecimal, 5},
+	{"361", "NET VOLUME (gal.)", "N6", kindDecimal, 5},
+	{"362", "VOLUME (qt), log", "N6", kindDecimal, 5},
+	{"363", "VOLUME (gal.), log", "N6", kindDecimal, 5},
+	{"364", "VOLUME (in³)", "N6", kindDecimal, 5},
+	{"365", "VOLUME (ft³)", "N6", kindDecimal, 5},
+	{"366", "VOLUME (yd³)", "N6", kindDecimal, 5},
+	{"367", "VOLUME (in³), log", "N6", kindDecimal, 5},
+	{"368", "VOLUME (ft³), log", "N6", kindDecimal, 5},
+	{"369", "VOLUME (yd³), log", "N6", kindDecimal, 5},
+	{"390", "AMOUNT", "N..15", kindDecimal, 9},
+	{"391", "AMOUNT", "N3+N..15", kindCurrency, 9},
+	{"392", "PRICE", "N..15", kindDecimal, 9},
+	{"393", "PRICE", "N3+N..15", kindCurrency, 9},
+	{"394", "PRCNT OFF", "N4", kindDecimal, 3},
+	{"395", "PRICE/UoM", "N6", kindDecimal, 5},
+}
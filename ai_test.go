@@ -0,0 +1,175 @@
+package upc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func fixedNow() func() {
+	timeNow = func() time.Time { return time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC) }
+	return func() { timeNow = time.Now }
+}
+
+func mustGtin(s string) Gtin {
+	g, err := ParseGtin(s)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+var elementTests = map[string]Elements{
+	"(01)00045496830434(17)251231(10)ABC": {
+		"01": mustGtin("00045496830434"),
+		"17": AIDate{Time: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+		"10": "ABC",
+	},
+	"]C1" + "0100045496830434" + "10ABC\x1d" + "3103001250" + "15260200": {
+		"01":   mustGtin("00045496830434"),
+		"10":   "ABC",
+		"3103": Decimal{1250, 3},
+		"15":   AIDate{time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), true},
+	},
+	"\x1d" + "00106141411234567897" + "0200045496830434" + "3712\x1d" + "4140614141000012": {
+		"00":  Sscc(10614141123456789),
+		"02":  mustGtin("00045496830434"),
+		"37":  12,
+		"414": "0614141000012",
+	},
+	"(3922)1999(3912)978550(21)A(B)C(7003)7512311830": {
+		"3922": Decimal{1999, 2},
+		"3912": CurrencyAmount{"978", Decimal{550, 2}},
+		"21":   "A(B)C",
+		"7003": AIDate{Time: time.Date(2075, 12, 31, 18, 30, 0, 0, time.UTC)},
+	},
+	"(4330)003250-(4333)000400(7041)PX(7258)1/2(8030)AQ-_=": {
+		"4330": "003250-",
+		"4333": "000400",
+		"7041": "PX",
+		"7258": "1/2",
+		"8030": "AQ-_=",
+	},
+}
+
+func TestElements(t *testing.T) {
+	defer fixedNow()()
+	for s, expect := range elementTests {
+		e, err := ParseElements(s)
+		if err != nil {
+			t.Errorf("%q: %s", s, err)
+		} else if !reflect.DeepEqual(e, expect) {
+			t.Errorf("%q: wrong elements\n got: %#v\nwant: %#v\n", s, e, expect)
+		}
+	}
+}
+
+func TestElementsData(t *testing.T) {
+	defer fixedNow()()
+	e, err := ParseElements("(01)00045496830434(3103)001250(15)260200(30)0012(7007)250101")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := map[string]string{
+		"01":   "00045496830434",
+		"3103": "001250",
+		"15":   "260200",
+		"30":   "12",
+		"7007": "250101",
+	}
+	for ai, want := range data {
+		if got, ok := e.Data(ai); !ok || got != want {
+			t.Errorf("(%s): wrong data: got %q", ai, got)
+		}
+	}
+	if _, ok := e.Data("10"); ok {
+		t.Errorf("(10): expected no data")
+	}
+	if g, ok := e.Gtin(); !ok || g.Gtin14() != "00045496830434" {
+		t.Errorf("wrong GTIN: got %s", g)
+	}
+	if d := e["3103"].(Decimal); d.String() != "1.250" || d.Float64() != 1.25 {
+		t.Errorf("wrong decimal: got %s", d)
+	}
+}
+
+func TestAIDate(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	dates := map[string]AIDate{
+		"750101": {time.Date(2075, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		"760101": {time.Date(1976, 1, 1, 0, 0, 0, 0, time.UTC), false},
+		"240200": {time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), true},
+		"991231": {time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC), false},
+	}
+	for s, want := range dates {
+		got, err := parseAIDate(s, now)
+		if err != nil || !got.Time.Equal(want.Time) || got.LastDayOfMonth != want.LastDayOfMonth {
+			t.Errorf("%s: wrong date: got %v, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"251301", "250230", "250001", "2512312460"} {
+		if _, err := parseAIDate(s, now); err != ErrAIDate {
+			t.Errorf("%s: expected ErrAIDate got %q", s, err)
+		}
+	}
+
+	// the day of 00 is kept by the flag, not the time
+	d, _ := parseAIDate("2402001830", now)
+	d.Time = d.Time.In(time.FixedZone("EST", -5*3600)).UTC()
+	if got := d.format("0601021504"); got != "2402001830" {
+		t.Errorf("wrong month end: got %s", got)
+	}
+	d.LastDayOfMonth = false
+	if got := d.format("060102"); got != "240229" {
+		t.Errorf("wrong date: got %s", got)
+	}
+}
+
+func TestElementsWrong(t *testing.T) {
+	errs := map[string]error{
+		"":                                     ErrElementSyntax,
+		"(01":                                  ErrElementSyntax,
+		"(01)00045496830434(10)ABC)":           nil,
+		"(89)123":                              ErrUnknownAI,
+		"8900":                                 ErrUnknownAI,
+		"(01)0004549683043":                    ErrAIDataLength,
+		"010004549683043":                      ErrAIDataLength,
+		"(10)ABCDEFGHIJKLMNOPQRSTU":            ErrAIDataLength,
+		"(10)":                                 ErrAIDataLength,
+		"(01)00045496830435":                   ErrAICheckDigit,
+		"(00)106141411234567890":               ErrAICheckDigit,
+		"(3103)00125x":                         ErrAIData,
+		"(3103)00125X":                         ErrAIData,
+		"(10)AB C":                             ErrAIData,
+		"(8010)abc":                            ErrAIData,
+		"(4330)003250+":                        ErrAIData,
+		"(4330)003250--":                       ErrAIDataLength,
+		"(8030)AQ.":                            ErrAIData,
+		"(7258)1/X":                            ErrAIData,
+		"(17)251301":                           ErrAIDate,
+		"(10)ABC(10)ABD":                       ErrAIDuplicate,
+		"(01)00045496830434(01)00045496830434": nil,
+	}
+	for s, want := range errs {
+		if _, err := ParseElements(s); err != want {
+			t.Errorf("%q: expected %q got %q", s, want, err)
+		}
+	}
+}
+
+func TestAITitle(t *testing.T) {
+	titles := map[string]string{
+		"10":   "BATCH/LOT",
+		"3103": "NET WEIGHT (kg)",
+		"7031": "PROCESSOR # 1",
+		"8030": "DIGSIG",
+		"95":   "INTERNAL",
+		"3106": "",
+		"9":    "",
+	}
+	for ai, want := range titles {
+		if got := AITitle(ai); got != want {
+			t.Errorf("(%s): expected %q got %q", ai, want, got)
+		}
+	}
+}
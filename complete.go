@@ -0,0 +1,106 @@
+package upc
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnknownDigits = errors.New("code must have at most one unknown digit")
+
+// CompleteUpc returns the valid UPC for s, which is either the first 11
+// digits of a UPC or all 12 digits with one unknown digit marked "?"
+// or "_".  The unknown digit may be the check digit.  A single unknown
+// digit always has exactly one solution.  The following errors can be
+// returned in addition to those of Parse:
+//
+//	ErrUnknownDigits
+func CompleteUpc(s string) (Upc, error) {
+	s, err := solveDigit(s, 12)
+	if err != nil {
+		return 0, err
+	}
+	return Parse(s)
+}
+
+// CompleteEan returns the valid EAN for s, which is either the first
+// 12 digits of an EAN or all 13 digits with one unknown digit.  It
+// works like CompleteUpc, with errors as from ParseEan.
+func CompleteEan(s string) (Ean, error) {
+	s, err := solveDigit(s, 13)
+	if err != nil {
+		return 0, err
+	}
+	return ParseEan(s)
+}
+
+// CompleteEan8 returns the valid EAN-8 for s, which is either the first
+// 7 digits of an EAN-8 or all 8 digits with one unknown digit.  It
+// works like CompleteUpc, with errors as from ParseEan8.
+func CompleteEan8(s string) (Ean8, error) {
+	s, err := solveDigit(s, 8)
+	if err != nil {
+		return 0, err
+	}
+	return ParseEan8(s)
+}
+
+// CompleteGtin returns the valid GTIN for s.  Without an unknown digit,
+// s is taken to lack its check digit, so 7, 11, 12 or 13 digits give
+// a GTIN-8, -12, -13 or -14.  With one unknown digit, s must be a
+// whole GTIN of 8, 12, 13 or 14 digits.  It works like CompleteUpc,
+// with errors as from ParseGtin.
+func CompleteGtin(s string) (Gtin, error) {
+	length := len(s)
+	if countUnknown(s) == 0 {
+		length++
+	}
+	s, err := solveDigit(s, length)
+	if err != nil {
+		return Gtin{}, err
+	}
+	return ParseGtin(s)
+}
+
+// countUnknown returns the number of unknown digits in s.
+func countUnknown(s string) int {
+	return strings.Count(s, "?") + strings.Count(s, "_")
+}
+
+// solveDigit replaces the unknown digit of s, a code of length digits
+// ending with a mod-10 check digit, with the only digit which makes
+// the check digit valid.  If s is one digit short and has no unknown
+// digit, the check digit is appended.  A string of the wrong length
+// is returned for the caller's parser to reject.
+func solveDigit(s string, length int) (string, error) {
+	unknown := countUnknown(s)
+	if unknown == 0 && len(s) == length-1 {
+		s += "?"
+		unknown = 1
+	}
+	switch {
+	case unknown == 0:
+		return s, nil
+	case unknown > 1:
+		return "", ErrUnknownDigits
+	}
+
+	i := strings.IndexAny(s, "?_")
+	zeroed := s[:i] + "0" + s[i+1:]
+	if len(s) != length {
+		return zeroed, nil
+	}
+	for _, b := range []byte(zeroed) {
+		if b < 48 || b > 57 {
+			return zeroed, nil
+		}
+	}
+
+	// The unknown digit d must bring the weighted sum to 0 mod 10.  Its
+	// weight is 1 or 3, and 7 is the inverse of 3 mod 10.
+	need := (10 - checkSum(zeroed)) % 10
+	d := need
+	if (len(s)-1-i)%2 == 1 {
+		d = need * 7 % 10
+	}
+	return s[:i] + string(rune('0'+d)) + s[i+1:], nil
+}
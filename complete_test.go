@@ -0,0 +1,79 @@
+package upc
+
+import "testing"
+
+func TestCompleteUpc(t *testing.T) {
+	inputs := []string{
+		"04549683043",
+		"04549683043?",
+		"?45496830434",
+		"045496_30434",
+		"0454968304_4",
+		"045496830434",
+	}
+	for _, s := range inputs {
+		u, err := CompleteUpc(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if u.String() != "045496830434" {
+			t.Errorf("%s: wrong UPC: got %s", s, u)
+		}
+	}
+
+	errs := map[string]error{
+		"045496_3043_":  ErrUnknownDigits,
+		"045496830435":  ErrInvalidCheckDigit,
+		"0454968304":    ErrTooShort,
+		"045496830?":    ErrTooShort,
+		"0454968304345": ErrTooLong,
+	}
+	for s, want := range errs {
+		if _, err := CompleteUpc(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+	if _, err := CompleteUpc("04549683x4?"); err == nil {
+		t.Errorf("expected an error got none")
+	}
+}
+
+func TestCompleteEan(t *testing.T) {
+	for _, s := range []string{"490237053648", "49023705?6485", "4902370536_85"} {
+		e, err := CompleteEan(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if e.String() != "4902370536485" {
+			t.Errorf("%s: wrong EAN: got %s", s, e)
+		}
+	}
+	for _, s := range []string{"9638507", "96385?74", "_6385074"} {
+		e, err := CompleteEan8(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if e.String() != "96385074" {
+			t.Errorf("%s: wrong EAN-8: got %s", s, e)
+		}
+	}
+}
+
+func TestCompleteGtin(t *testing.T) {
+	gtins := map[string]string{
+		"9638507":        "96385074",
+		"04549683043":    "045496830434",
+		"490237053648":   "4902370536485",
+		"1061414100001":  "10614141000019",
+		"1061414100?019": "10614141000019",
+		"96385?74":       "96385074",
+	}
+	for s, want := range gtins {
+		g, err := CompleteGtin(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if g.String() != want {
+			t.Errorf("%s: wrong GTIN: got %s", s, g)
+		}
+	}
+	if _, err := CompleteGtin("1234"); err != ErrGtinLength {
+		t.Errorf("expected ErrGtinLength got %q", err)
+	}
+}
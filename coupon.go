@@ -0,0 +1,240 @@
+package upc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Coupon is a North American coupon in the GS1 DataBar Expanded
+// format, which replaced number system 5 UPCs.  It's the content of
+// application identifier 8110, or of 8112 for paperless coupons.
+// Optional fields are nil or zero when absent from the coupon.
+type Coupon struct {
+	Paperless     bool   // AI 8112 rather than 8110
+	CompanyPrefix string // GS1 company prefix of the issuer or funder
+	OfferCode     string // 6 digits assigned by the issuer
+
+	// Fields only present on AI 8110 coupons.
+	SaveValue               int // in cents, unless Misc says otherwise
+	Primary                 Purchase
+	AdditionalPurchaseRules int // 0-3, only meaningful with Second
+	Second                  *Purchase
+	Third                   *Purchase
+	Expiration              time.Time
+	Start                   time.Time
+	RetailerId              string
+	Misc                    *CouponMisc
+
+	// Fields only present on AI 8112 coupons.
+	FormatIdentifier int
+
+	SerialNumber string
+}
+
+// Purchase is a purchase requirement of a coupon.
+type Purchase struct {
+	Requirement     int    // a number of units or an amount in cents
+	RequirementCode int    // what Requirement counts
+	FamilyCode      string // 3 digits assigned by the issuer
+	CompanyPrefix   string // company prefix of the qualifying products
+}
+
+// CouponMisc holds the miscellaneous elements of a coupon.
+type CouponMisc struct {
+	SaveValueCode      int // how SaveValue applies, 0 for cents off
+	SaveValueAppliesTo int // which qualifying item it applies to
+	StoreCoupon        bool
+	DontMultiply       bool
+}
+
+var ErrNotCoupon = errors.New("not a GS1 coupon (must begin with AI 8110 or 8112)")
+var ErrCouponTooLong = errors.New("coupon data is too long (must be at most 70 characters)")
+var ErrCouponTruncated = errors.New("coupon data ends in the middle of a field")
+var ErrCouponVli = errors.New("coupon has an invalid variable length indicator")
+var ErrCouponField = errors.New("coupon has an unknown, repeated or misordered data field")
+var ErrCouponDate = errors.New("coupon has an invalid date")
+var ErrCouponRules = errors.New("coupon has invalid additional purchase rules (must be 0-3)")
+
+// ParseCoupon parses the element string of a GS1 DataBar coupon, with
+// its application identifier in parentheses or not, as in
+// "(8110)106141412345621011123..." or "8112...".  The following
+// errors can be returned in addition to integer parsing errors:
+//
+//	ErrNotCoupon
+//	ErrCouponTooLong
+//	ErrCouponTruncated
+//	ErrCouponVli
+//	ErrCouponField
+//	ErrCouponDate
+//	ErrCouponRules
+func ParseCoupon(s string) (Coupon, error) {
+	var c Coupon
+	switch {
+	case strings.HasPrefix(s, "(8110)"), strings.HasPrefix(s, "(8112)"):
+		c.Paperless = s[4] == '2'
+		s = s[6:]
+	case strings.HasPrefix(s, "8110"), strings.HasPrefix(s, "8112"):
+		c.Paperless = s[3] == '2'
+		s = s[4:]
+	default:
+		return Coupon{}, ErrNotCoupon
+	}
+	if len(s) > 70 {
+		return Coupon{}, ErrCouponTooLong
+	}
+	for _, b := range []byte(s) {
+		if b < 48 || b > 57 {
+			return Coupon{}, fmt.Errorf("Invalid coupon digit: %c", b)
+		}
+	}
+
+	r := &couponReader{s: s}
+	if c.Paperless {
+		c.FormatIdentifier = r.number(1)
+		c.CompanyPrefix = r.digits(r.vli(0, 6) + 6)
+		c.OfferCode = r.digits(6)
+		c.SerialNumber = r.digits(r.vli(0, 9) + 6)
+		if err := r.finish(); err != nil {
+			return Coupon{}, err
+		}
+		return c, nil
+	}
+
+	c.CompanyPrefix = r.digits(r.vli(0, 6) + 6)
+	c.OfferCode = r.digits(6)
+	c.SaveValue = r.number(r.vli(1, 5))
+	c.Primary = Purchase{
+		Requirement:     r.number(r.vli(1, 5)),
+		RequirementCode: r.number(1),
+		FamilyCode:      r.digits(3),
+		CompanyPrefix:   c.CompanyPrefix,
+	}
+
+	last := 0
+	for r.err == nil && len(r.s) > 0 {
+		field := r.number(1)
+		if field <= last {
+			return Coupon{}, ErrCouponField
+		}
+		last = field
+
+		switch field {
+		case 1:
+			c.AdditionalPurchaseRules = r.number(1)
+			if r.err == nil && c.AdditionalPurchaseRules > 3 {
+				return Coupon{}, ErrCouponRules
+			}
+			c.Second = r.purchase(c.CompanyPrefix)
+		case 2:
+			c.Third = r.purchase(c.CompanyPrefix)
+		case 3:
+			c.Expiration = r.date()
+		case 4:
+			c.Start = r.date()
+		case 5:
+			c.SerialNumber = r.digits(r.vli(0, 9) + 6)
+		case 6:
+			c.RetailerId = r.digits(r.vli(1, 7) + 6)
+		case 9:
+			c.Misc = &CouponMisc{
+				SaveValueCode:      r.number(1),
+				SaveValueAppliesTo: r.number(1),
+				StoreCoupon:        r.number(1) == 1,
+				DontMultiply:       r.number(1) == 1,
+			}
+		default:
+			return Coupon{}, ErrCouponField
+		}
+	}
+	if err := r.finish(); err != nil {
+		return Coupon{}, err
+	}
+	return c, nil
+}
+
+// couponReader consumes the fields of a coupon.  The first error
+// sticks, and later reads return zero values.
+type couponReader struct {
+	s   string
+	err error
+}
+
+// digits returns the next n digits.
+func (r *couponReader) digits(n int) string {
+	if r.err != nil {
+		return ""
+	}
+	if len(r.s) < n {
+		r.err = ErrCouponTruncated
+		return ""
+	}
+	d := r.s[:n]
+	r.s = r.s[n:]
+	return d
+}
+
+// number returns the next n digits as an integer.
+func (r *couponReader) number(n int) int {
+	var v int
+	for _, b := range []byte(r.digits(n)) {
+		v *= 10
+		v += int(b - 48)
+	}
+	return v
+}
+
+// vli returns the next variable length indicator, which must be from
+// min to max.
+func (r *couponReader) vli(min, max int) int {
+	v := r.number(1)
+	if r.err == nil && (v < min || v > max) {
+		r.err = ErrCouponVli
+	}
+	return v
+}
+
+// purchase returns a second or third qualifying purchase.  A company
+// prefix indicator of 9 means the primary company prefix applies.
+func (r *couponReader) purchase(primary string) *Purchase {
+	p := &Purchase{
+		Requirement:     r.number(r.vli(1, 5)),
+		RequirementCode: r.number(1),
+		FamilyCode:      r.digits(3),
+	}
+	if v := r.number(1); v == 9 {
+		p.CompanyPrefix = primary
+	} else if v <= 6 {
+		p.CompanyPrefix = r.digits(v + 6)
+	} else if r.err == nil {
+		r.err = ErrCouponVli
+	}
+	return p
+}
+
+// date returns the next YYMMDD date.  Coupon dates are always in the
+// 21st century.
+func (r *couponReader) date() time.Time {
+	s := r.digits(6)
+	if r.err != nil {
+		return time.Time{}
+	}
+	year := 2000 + int(s[0]-48)*10 + int(s[1]-48)
+	month := time.Month(int(s[2]-48)*10 + int(s[3]-48))
+	day := int(s[4]-48)*10 + int(s[5]-48)
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if t.Month() != month || t.Day() != day {
+		r.err = ErrCouponDate
+		return time.Time{}
+	}
+	return t
+}
+
+// finish returns the first error, if any.
+func (r *couponReader) finish() error {
+	if r.err == nil && len(r.s) > 0 {
+		return ErrCouponField
+	}
+	return r.err
+}
@@ -0,0 +1,122 @@
+package upc
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var couponTests = map[string]Coupon{
+	// only the required fields
+	"(8110)10614141123456250110123": {
+		CompanyPrefix: "0614141",
+		OfferCode:     "123456",
+		SaveValue:     50,
+		Primary: Purchase{
+			Requirement:     1,
+			RequirementCode: 0,
+			FamilyCode:      "123",
+			CompanyPrefix:   "0614141",
+		},
+	},
+
+	// qualifying purchases and dates
+	"8110" + "1" + "0614141" + "123456" + "2" + "50" + "1" + "1" + "0" + "123" +
+		"1" + "0" + "1" + "2" + "0" + "456" + "9" +
+		"2" + "3" + "100" + "1" + "789" + "0" + "012345" +
+		"3" + "251231" +
+		"4" + "250101": {
+		CompanyPrefix: "0614141",
+		OfferCode:     "123456",
+		SaveValue:     50,
+		Primary: Purchase{
+			Requirement:     1,
+			RequirementCode: 0,
+			FamilyCode:      "123",
+			CompanyPrefix:   "0614141",
+		},
+		AdditionalPurchaseRules: 0,
+		Second: &Purchase{
+			Requirement:     2,
+			RequirementCode: 0,
+			FamilyCode:      "456",
+			CompanyPrefix:   "0614141",
+		},
+		Third: &Purchase{
+			Requirement:     100,
+			RequirementCode: 1,
+			FamilyCode:      "789",
+			CompanyPrefix:   "012345",
+		},
+		Expiration: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC),
+		Start:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	},
+
+	// serial number, retailer and miscellaneous elements
+	"8110" + "1" + "0614141" + "123456" + "3" + "100" + "1" + "2" + "1" + "000" +
+		"5" + "0" + "123456" +
+		"6" + "1" + "0614141" +
+		"9" + "0010": {
+		CompanyPrefix: "0614141",
+		OfferCode:     "123456",
+		SaveValue:     100,
+		Primary: Purchase{
+			Requirement:     2,
+			RequirementCode: 1,
+			FamilyCode:      "000",
+			CompanyPrefix:   "0614141",
+		},
+		SerialNumber: "123456",
+		RetailerId:   "0614141",
+		Misc: &CouponMisc{
+			StoreCoupon: true,
+		},
+	},
+
+	// paperless
+	"(8112)0" + "1" + "0614141" + "123456" + "2" + "12345678": {
+		Paperless:     true,
+		CompanyPrefix: "0614141",
+		OfferCode:     "123456",
+		SerialNumber:  "12345678",
+	},
+}
+
+func TestCoupon(t *testing.T) {
+	for s, expect := range couponTests {
+		c, err := ParseCoupon(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if !reflect.DeepEqual(c, expect) {
+			t.Errorf("%s: wrong coupon\n got: %#v\nwant: %#v\n", s, c, expect)
+		}
+	}
+}
+
+func TestCouponWrong(t *testing.T) {
+	errs := map[string]error{
+		"":                   ErrNotCoupon,
+		"(01)00045496830434": ErrNotCoupon,
+		"8110" + "10614141123456250110123" + strings.Repeat("0", 48):              ErrCouponTooLong,
+		"811010614141123456250110":                                                ErrCouponTruncated,
+		"811070614141123456250110123":                                             ErrCouponVli,
+		"811010614141123456050110123":                                             ErrCouponVli,
+		"811010614141123456250110123" + "7":                                       ErrCouponField,
+		"811010614141123456250110123" + "4250101" + "3251231":                     ErrCouponField,
+		"811010614141123456250110123" + "3251231" + "3251231":                     ErrCouponField,
+		"811010614141123456250110123" + "3251301":                                 ErrCouponDate,
+		"811010614141123456250110123" + "3250230":                                 ErrCouponDate,
+		"811010614141123456250110123" + "2" + "1" + "1" + "0" + "789" + "8":       ErrCouponVli,
+		"811010614141123456250110123" + "1" + "4" + "1" + "1" + "0" + "789" + "9": ErrCouponRules,
+		"(8112)0" + "1" + "0614141" + "123456" + "2" + "12345678" + "1":           ErrCouponField,
+	}
+	for s, want := range errs {
+		if _, err := ParseCoupon(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+	if _, err := ParseCoupon("8110106141411234562501101x3"); err == nil {
+		t.Errorf("expected an error got none")
+	}
+}
@@ -0,0 +1,729 @@
+package upc
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+var ErrNoBarcode = errors.New("no barcode found")
+var ErrRunWidth = errors.New("bar and space widths can't be negative")
+
+// Barcode is a barcode read from an image or the widths of its bars.
+type Barcode struct {
+	Format SymbolFormat
+	Digits string // the encoded digits, including the check digit
+	AddOn  AddOn  // the add-on, if any
+
+	// Confidence is from 0 to 1: how much better the widths of the bars
+	// and spaces fit the digits read than any other digits, on average.
+	// It's 1 for a perfectly printed barcode.
+	Confidence float64
+
+	// Bounds is the part of the image in which the bars were read,
+	// including those of the add-on.
+	Bounds image.Rectangle
+}
+
+// Gtin returns the code of the barcode as a GTIN.  A UPC-E is expanded
+// into its UPC-A.
+func (b Barcode) Gtin() Gtin {
+	if b.Format == SymbolUpcE {
+		e, _ := ParseUpcE(b.Digits)
+		return e.Upc().Gtin()
+	}
+	g, _ := ParseGtin(b.Digits)
+	return g
+}
+
+// Upc returns the code of the barcode as a Upc.  A UPC-E is expanded
+// into its UPC-A.  The second return value is false for an EAN-8 or an
+// EAN-13 not beginning with 0.
+func (b Barcode) Upc() (Upc, bool) {
+	if b.Format == SymbolEan8 {
+		return 0, false
+	}
+	return b.Gtin().Upc()
+}
+
+// Ean returns the code of the barcode as an Ean.  The second return
+// value is false for an EAN-8.
+func (b Barcode) Ean() (Ean, bool) {
+	if b.Format == SymbolEan8 {
+		return 0, false
+	}
+	return b.Gtin().Ean()
+}
+
+// DecodeImage reads the UPC-A, UPC-E, EAN-13, EAN-8 and ITF-14 barcodes
+// of an image, with their add-ons.  Rows and columns of the image are
+// scanned in both directions, so the bars may run across or down the
+// image, and either way up.  Dark bars on a light background are read
+// as well as light bars on a dark one.  Each scanline is split into
+// bars and spaces by a threshold which follows the local contrast, with
+// edges placed to a fraction of a pixel, which allows for uneven
+// lighting, blur across a module or so and a skew of 15 degrees or so,
+// or 10 for an ITF-14, whose bars are short for its width.
+//
+// A barcode is returned once however many scanlines read it, with the
+// mean confidence of the reads, and the barcodes read by the most
+// scanlines come first.  Unless the image is a single scanline, a
+// barcode must be read by two of them.  ErrNoBarcode is returned if
+// there's none.
+func DecodeImage(img image.Image) ([]Barcode, error) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	pix := luminance(img)
+
+	var t tally
+	line := make([]float64, w+h)
+	for y := 0; y < h; y += scanStep(h) {
+		for x := 0; x < w; x++ {
+			line[x] = float64(pix[y*w+x])
+		}
+		for _, r := range scanLine(line[:w]) {
+			t.add(r.b, image.Rect(int(math.Floor(r.from)), y, int(math.Ceil(r.to)), y+1).Add(b.Min))
+		}
+	}
+	for x := 0; x < w; x += scanStep(w) {
+		for y := 0; y < h; y++ {
+			line[y] = float64(pix[y*w+x])
+		}
+		for _, r := range scanLine(line[:h]) {
+			t.add(r.b, image.Rect(x, int(math.Floor(r.from)), x+1, int(math.Ceil(r.to))).Add(b.Min))
+		}
+	}
+
+	// a misread is rarely repeated by the next scanline
+	minLines := 2
+	if w == 1 || h == 1 {
+		minLines = 1
+	}
+	barcodes := t.barcodes(minLines)
+	if len(barcodes) == 0 {
+		return nil, ErrNoBarcode
+	}
+	return barcodes, nil
+}
+
+// scanStep returns the distance between the scanlines across a length
+// of image, so that large images don't take long to scan.
+func scanStep(length int) int {
+	if length < 512 {
+		return 1
+	}
+	return length / 256
+}
+
+// luminance returns the luminance of each pixel of an image, row by row.
+func luminance(img image.Image) []uint8 {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	pix := make([]uint8, w*h)
+	switch m := img.(type) {
+	case *image.Gray:
+		for y := 0; y < h; y++ {
+			i := m.PixOffset(b.Min.X, b.Min.Y+y)
+			copy(pix[y*w:(y+1)*w], m.Pix[i:i+w])
+		}
+	case *image.YCbCr:
+		// the luma of a JPEG photo is all there is to read
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				pix[y*w+x] = m.Y[m.YOffset(b.Min.X+x, b.Min.Y+y)]
+			}
+		}
+	default:
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				pix[y*w+x] = color.GrayModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.Gray).Y
+			}
+		}
+	}
+	return pix
+}
+
+// tally accumulates the barcodes read by each scanline.
+type tally struct {
+	reads []*tallied
+}
+
+// tallied is a barcode with the number of scanlines which read it, and
+// how many of them read each add-on.
+type tallied struct {
+	b          Barcode
+	lines      int
+	confidence float64 // sum of the confidence of each read
+	addOns     map[AddOn]int
+}
+
+// add counts a barcode read within the given part of the image.
+func (t *tally) add(b Barcode, r image.Rectangle) {
+	var e *tallied
+	for _, x := range t.reads {
+		if x.b.Format == b.Format && x.b.Digits == b.Digits {
+			e = x
+			break
+		}
+	}
+	if e == nil {
+		e = &tallied{b: b, addOns: map[AddOn]int{}}
+		e.b.Bounds = r
+		t.reads = append(t.reads, e)
+	}
+	e.lines++
+	e.confidence += b.Confidence
+	e.b.Bounds = e.b.Bounds.Union(r)
+	if b.AddOn.Len() > 0 {
+		e.addOns[b.AddOn]++
+	}
+}
+
+// barcodes returns the barcodes read by at least minLines scanlines,
+// with the add-on read by the most scanlines, since those which cross
+// the digits above the add-on miss it.
+//
+// A scanline which leaves the bars of a skewed EAN-13 just after its
+// centre guard can read the left half as a UPC-E, since the centre
+// guard and the next bar look like the UPC-E guard, and the sets of an
+// EAN-13 are those of a UPC-E with some check digit.  A UPC-E encoding
+// the left half of another barcode read is dropped.  So is a barcode
+// overlapping a different one read by more scanlines, which is a
+// misread of part of it.  Barcodes side by side are all returned.
+func (t *tally) barcodes(minLines int) []Barcode {
+	sort.SliceStable(t.reads, func(i, j int) bool {
+		return t.reads[i].lines > t.reads[j].lines
+	})
+	halves := map[string]bool{}
+	for _, e := range t.reads {
+		switch e.b.Format {
+		case SymbolEan13:
+			halves[e.b.Digits[1:7]] = true
+		case SymbolUpcA:
+			halves[e.b.Digits[:6]] = true
+		}
+	}
+	var barcodes []Barcode
+reads:
+	for _, e := range t.reads {
+		if e.lines < minLines || e.b.Format == SymbolUpcE && halves[e.b.Digits[1:7]] {
+			continue
+		}
+		for _, b := range barcodes {
+			if b.Bounds.Overlaps(e.b.Bounds) && b.Digits != e.b.Digits {
+				continue reads
+			}
+		}
+		b := e.b
+		b.Confidence = e.confidence / float64(e.lines)
+		b.AddOn = AddOn{}
+		best := 0
+		for a, n := range e.addOns {
+			if n > best || n == best && a.String() < b.AddOn.String() {
+				b.AddOn, best = a, n
+			}
+		}
+		barcodes = append(barcodes, b)
+	}
+	return barcodes
+}
+
+// Contrast needed to read a scanline, out of 255.
+const minContrast = 24
+
+// scanRead is a barcode read from a scanline, with the positions of its
+// first and last bar.
+type scanRead struct {
+	b        Barcode
+	from, to float64
+}
+
+// scanLine reads the barcodes of a scanline of luminance values, in
+// both directions and with both colours as bars.  Bars read one way can
+// sometimes be read as another barcode the other way, most often a
+// UPC-E, so of the barcodes read across the same bars, only the one
+// read with the most confidence is kept.
+func scanLine(v []float64) []scanRead {
+	runs, edges := binarize(v)
+	if runs == nil {
+		return nil
+	}
+	var reads []scanRead
+	for inverted := 0; inverted < 2; inverted++ {
+		for reversed := 0; reversed < 2; reversed++ {
+			for _, r := range readLine(runs) {
+				from, to := edges[r.first], edges[r.end]
+				if from > to {
+					from, to = to, from
+				}
+				reads = append(reads, scanRead{r.b, from, to})
+			}
+			runs, edges = reverse(runs), reverse(edges)
+		}
+		// a space of no width before and after turns spaces into bars
+		runs = append(append([]float64{0}, runs...), 0)
+		edges = append(append([]float64{edges[0]}, edges...), edges[len(edges)-1])
+	}
+
+	sort.SliceStable(reads, func(i, j int) bool {
+		return reads[i].b.Confidence > reads[j].b.Confidence
+	})
+	var kept []scanRead
+reads:
+	for _, r := range reads {
+		for _, k := range kept {
+			if r.from < k.to && k.from < r.to {
+				continue reads
+			}
+		}
+		kept = append(kept, r)
+	}
+	return kept
+}
+
+// reverse returns the values in reverse order.
+func reverse(v []float64) []float64 {
+	r := make([]float64, len(v))
+	for i := range v {
+		r[len(v)-1-i] = v[i]
+	}
+	return r
+}
+
+// binarize splits a scanline of luminance values into light and dark
+// runs, starting and ending with a light one, which may have no width.
+// It returns their widths and the positions of their edges, which are
+// placed where the values cross the threshold between two pixels.  The
+// threshold is midway between the darkest and lightest values nearby,
+// or across the whole scanline where there's little contrast nearby.
+func binarize(v []float64) (runs, edges []float64) {
+	n := len(v)
+	if n < 3 {
+		return nil, nil
+	}
+	s := make([]float64, n)
+	lo, hi := 255.0, 0.0
+	for i := range v {
+		l, r := v[i], v[i]
+		if i > 0 {
+			l = v[i-1]
+		}
+		if i < n-1 {
+			r = v[i+1]
+		}
+		s[i] = (l + 2*v[i] + r) / 4
+		lo, hi = math.Min(lo, s[i]), math.Max(hi, s[i])
+	}
+	if hi-lo < minContrast {
+		return nil, nil
+	}
+
+	localLo, localHi := envelope(s, n/32+2)
+	t := make([]float64, n)
+	for i := range s {
+		if localHi[i]-localLo[i] >= (hi-lo)/4 {
+			t[i] = (localLo[i] + localHi[i]) / 2
+		} else {
+			t[i] = (lo + hi) / 2
+		}
+	}
+
+	edges = []float64{0}
+	dark := false
+	for i := range s {
+		d := s[i] < t[i]
+		if d == dark {
+			continue
+		}
+		pos := 0.0
+		if i > 0 {
+			a, b := s[i-1]-t[i-1], s[i]-t[i]
+			pos = float64(i) - 0.5
+			if a != b {
+				pos += a / (a - b)
+			}
+		}
+		edges = append(edges, pos)
+		dark = d
+	}
+	if dark {
+		edges = append(edges, float64(n))
+	}
+	edges = append(edges, float64(n))
+
+	runs = make([]float64, len(edges)-1)
+	for i := range runs {
+		runs[i] = edges[i+1] - edges[i]
+	}
+	return runs, edges
+}
+
+// envelope returns the lowest and highest values within r of each
+// value.
+func envelope(v []float64, r int) (lo, hi []float64) {
+	n := len(v)
+	lo, hi = make([]float64, n), make([]float64, n)
+	// indexes of the values which may yet be the lowest or highest of a
+	// window, in order
+	var lows, highs []int
+	for j := 0; j < n+r; j++ {
+		if j < n {
+			for len(lows) > 0 && v[lows[len(lows)-1]] >= v[j] {
+				lows = lows[:len(lows)-1]
+			}
+			lows = append(lows, j)
+			for len(highs) > 0 && v[highs[len(highs)-1]] <= v[j] {
+				highs = highs[:len(highs)-1]
+			}
+			highs = append(highs, j)
+		}
+		i := j - r
+		if i < 0 {
+			continue
+		}
+		for lows[0] < i-r {
+			lows = lows[1:]
+		}
+		for highs[0] < i-r {
+			highs = highs[1:]
+		}
+		lo[i], hi[i] = v[lows[0]], v[highs[0]]
+	}
+	return lo, hi
+}
+
+// DecodeRuns reads a UPC-A, UPC-E, EAN-13, EAN-8 or ITF-14 barcode,
+// with its add-on, from the widths of the bars and spaces along a
+// scanline, such as a laser scanner or linear CCD sensor measures.  The
+// widths may be in any unit, such as pixels or clock ticks, and
+// alternate between spaces and bars, starting with the space before
+// the barcode, which may have no width.
+//
+// The width of a module is found from each digit, so it may vary across
+// the barcode.  The digits before the centre guard may be in the L or G
+// set, and their sets give the leading digit of an EAN-13 or the number
+// system and check digit of a UPC-E.  The barcode may be read in either
+// direction.  If the widths hold more than one barcode, the one read
+// with the most confidence is returned.  Its Bounds are empty.  The
+// following errors can be returned:
+//
+//	ErrRunWidth
+//	ErrNoBarcode
+func DecodeRuns(widths []float64) (Barcode, error) {
+	runs := make([]float64, len(widths), len(widths)+1)
+	for i, w := range widths {
+		if w < 0 || math.IsNaN(w) {
+			return Barcode{}, ErrRunWidth
+		}
+		runs[i] = w
+	}
+	if len(runs)%2 == 0 {
+		// end with a space, so the reversed runs start with one
+		runs = append(runs, 0)
+	}
+
+	var best Barcode
+	for reversed := 0; reversed < 2; reversed++ {
+		for _, r := range readLine(runs) {
+			if r.b.Confidence > best.Confidence || best.Format == 0 {
+				best = r.b
+			}
+		}
+		runs = reverse(runs)
+	}
+	if best.Format == 0 {
+		return Barcode{}, ErrNoBarcode
+	}
+	return best, nil
+}
+
+// lineRead is a barcode read from runs of bars and spaces, with the
+// index of its first bar and of the run after its last bar.
+type lineRead struct {
+	b          Barcode
+	first, end int
+}
+
+// readLine reads the barcodes of a scanline of runs of bars and
+// spaces, which starts with a space.
+func readLine(runs []float64) []lineRead {
+	var reads []lineRead
+	for i := 1; i+2 < len(runs); i += 2 {
+		if b, end, ok := readSymbol(runs, i); ok {
+			reads = append(reads, lineRead{b, i, end})
+			i = end - 1
+		}
+	}
+	return reads
+}
+
+// Largest differences in modules between the measured and expected
+// widths of a bar or space, and of the sum of those of a digit.
+const (
+	maxVariance      = 0.7
+	maxDigitVariance = 1.5
+)
+
+// Smallest quiet zones accepted, in modules.  They're less than the
+// standard, for tightly cropped images.  The gap before an add-on is
+// 7 to 12 modules in the standard.
+const (
+	minQuiet      = 5
+	minAddOnQuiet = 3
+	minAddOnGap   = 5
+	maxAddOnGap   = 14
+)
+
+// digitWidths holds the widths in modules of the bars and spaces of each
+// digit of the L, G and R sets.
+var digitWidths = map[byte][10][]float64{}
+
+func init() {
+	for set, patterns := range symbolSets {
+		var w [10][]float64
+		for d, p := range patterns {
+			w[d] = widths(p)
+		}
+		digitWidths[set] = w
+	}
+}
+
+// widths returns the widths in modules of the runs of bars and spaces
+// of a pattern written as 1 for a bar and 0 for a space.
+func widths(p string) []float64 {
+	var w []float64
+	for i := range p {
+		if i == 0 || p[i] != p[i-1] {
+			w = append(w, 0)
+		}
+		w[len(w)-1]++
+	}
+	return w
+}
+
+// readSymbol reads a symbol whose left guard begins with the bar at run
+// i, and its add-on, if any, or an ITF-14 whose start pattern begins
+// there.  It returns the barcode and the index of the run after its
+// last bar.
+func readSymbol(runs []float64, i int) (Barcode, int, bool) {
+	if i+3 > len(runs) {
+		return Barcode{}, 0, false
+	}
+	m := (runs[i] + runs[i+1] + runs[i+2]) / 3
+	if !fits(runs[i:i+3], normalGuard, m) || i > 1 && runs[i-1] < minQuiet*m {
+		return Barcode{}, 0, false
+	}
+	for _, f := range []SymbolFormat{SymbolEan13, SymbolEan8, SymbolUpcE} {
+		r := &runReader{runs: runs, i: i + 3, m: m, ok: true}
+		b, ok := r.symbol(f)
+		if !ok || !r.quiet(minQuiet) {
+			continue
+		}
+		if a, after, ok := r.addOn(); ok {
+			b.AddOn, r = a, after
+		}
+		b.Confidence = r.confidence / float64(r.digits)
+		return b, r.i, true
+	}
+	return readItf(runs, i)
+}
+
+// addOn reads the add-on after the gap at the next run.  It returns the
+// add-on and a reader which has read it, leaving r as it was.
+func (r *runReader) addOn() (AddOn, *runReader, bool) {
+	if r.i+1 >= len(r.runs) || r.runs[r.i] < minAddOnGap*r.m || r.runs[r.i] > maxAddOnGap*r.m {
+		return AddOn{}, nil, false
+	}
+	for _, n := range []int{5, 2} {
+		a := *r
+		a.i++
+		a.guard(addOnGuard)
+		var digits, parity []byte
+		for k := 0; k < n; k++ {
+			if k > 0 {
+				a.guard(addOnSep)
+			}
+			d, set := a.digit("LG")
+			digits, parity = append(digits, d), append(parity, set)
+		}
+		if !a.ok || !a.quiet(minAddOnQuiet) {
+			continue
+		}
+		addOn, err := ParseAddOn(string(digits))
+		if err != nil {
+			continue
+		}
+		want := addOn2Parity[addOn.Value()%4]
+		if n == 5 {
+			want = addOn5Parity[addOn5Checksum(addOn.String())]
+		}
+		if string(parity) == want {
+			return addOn, &a, true
+		}
+	}
+	return AddOn{}, nil, false
+}
+
+// fits reports whether runs match a guard pattern, given the width of a
+// module.
+func fits(runs []float64, p string, m float64) bool {
+	want := widths(p)
+	if len(runs) < len(want) {
+		return false
+	}
+	for k, w := range want {
+		if math.Abs(runs[k]/m-w) > maxVariance {
+			return false
+		}
+	}
+	return true
+}
+
+// runReader reads the digits and guards of a symbol from runs of bars
+// and spaces.  Once a read fails, ok is false and the rest are ignored.
+type runReader struct {
+	runs []float64
+	i    int     // index of the next run
+	m    float64 // width of a module where the last digit was read
+	ok   bool
+
+	confidence float64 // sum of the confidence of each digit read
+	digits     int
+}
+
+// guard reads a guard pattern.
+func (r *runReader) guard(p string) {
+	n := len(widths(p))
+	if !r.ok || r.i+n > len(r.runs) || !fits(r.runs[r.i:r.i+n], p, r.m) {
+		r.ok = false
+		return
+	}
+	r.i += n
+}
+
+// digit reads a digit in one of the given sets.  It returns the digit
+// and its set.  Each digit is 7 modules wide, which gives the width of
+// a module there, however the scale varies across the symbol.  The
+// confidence in the digit is how much nearer the widths are to it than
+// to the next nearest digit.
+func (r *runReader) digit(sets string) (byte, byte) {
+	if !r.ok || r.i+4 > len(r.runs) {
+		r.ok = false
+		return 0, 0
+	}
+	w := r.runs[r.i : r.i+4]
+	total := w[0] + w[1] + w[2] + w[3]
+	if total <= 0 {
+		r.ok = false
+		return 0, 0
+	}
+
+	var digit, set byte
+	best, next := maxDigitVariance, maxDigitVariance
+	for _, s := range []byte(sets) {
+		for d, want := range digitWidths[s] {
+			var sum float64
+			for k := range want {
+				v := math.Abs(w[k]*7/total - want[k])
+				if v > maxVariance {
+					sum = math.Inf(1)
+					break
+				}
+				sum += v
+			}
+			if sum < best {
+				best, next, digit, set = sum, best, byte('0'+d), s
+			} else if sum < next {
+				next = sum
+			}
+		}
+	}
+	if set == 0 {
+		r.ok = false
+		return 0, 0
+	}
+	r.m = total / 7
+	r.i += 4
+	r.confidence += 1 - best/next
+	r.digits++
+	return digit, set
+}
+
+// quiet reports whether the next run is a space of at least n modules,
+// or the last run of the scanline.
+func (r *runReader) quiet(n float64) bool {
+	return r.ok && (r.i == len(r.runs)-1 || r.i < len(r.runs) && r.runs[r.i] >= n*r.m)
+}
+
+// symbol reads the digits and guards of a symbol after its left guard.
+// The parity of the digits gives the leading digit of an EAN-13, and
+// the number system and check digit of a UPC-E.
+func (r *runReader) symbol(f SymbolFormat) (Barcode, bool) {
+	left, sets := 6, "LG"
+	if f == SymbolEan8 {
+		left, sets = 4, "L"
+	}
+	var digits, parity []byte
+	for k := 0; k < left; k++ {
+		d, set := r.digit(sets)
+		digits, parity = append(digits, d), append(parity, set)
+	}
+	if f == SymbolUpcE {
+		r.guard(upcEGuard)
+	} else {
+		r.guard(centreGuard)
+		for k := 0; k < left; k++ {
+			d, _ := r.digit("R")
+			digits = append(digits, d)
+		}
+		r.guard(normalGuard)
+	}
+	if !r.ok {
+		return Barcode{}, false
+	}
+
+	b := Barcode{Format: f}
+	var err error
+	switch f {
+	case SymbolEan13:
+		first := indexOf(ean13Parity[:], string(parity))
+		if first < 0 {
+			return Barcode{}, false
+		}
+		b.Digits = string(rune('0'+first)) + string(digits)
+		_, err = ParseEan(b.Digits)
+		if first == 0 {
+			b.Format, b.Digits = SymbolUpcA, b.Digits[1:]
+		}
+	case SymbolEan8:
+		b.Digits = string(digits)
+		_, err = ParseEan8(b.Digits)
+	case SymbolUpcE:
+		ns, check := 0, indexOf(upcEParity[:], string(parity))
+		if check < 0 {
+			// number system 1 swaps the sets
+			for k := range parity {
+				parity[k] = 'L' + 'G' - parity[k]
+			}
+			ns, check = 1, indexOf(upcEParity[:], string(parity))
+		}
+		if check < 0 {
+			return Barcode{}, false
+		}
+		b.Digits = string(rune('0'+ns)) + string(digits) + string(rune('0'+check))
+		_, err = ParseUpcE(b.Digits)
+	}
+	return b, err == nil
+}
+
+// indexOf returns the index of a string in a table, or -1.
+func indexOf(table []string, s string) int {
+	for i := range table {
+		if table[i] == s {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,293 @@
+package upc
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDecodeImage(t *testing.T) {
+	codes := map[string]SymbolFormat{
+		"036000291452":       SymbolUpcA,
+		"4006381333931":      SymbolEan13,
+		"96385074":           SymbolEan8,
+		"04252614":           SymbolUpcE,
+		"14252611":           SymbolUpcE,
+		"036000291452 52495": SymbolUpcA,
+		"9780306406157 12":   SymbolEan13,
+	}
+	distortions := map[string]func(*image.Gray) *image.Gray{
+		"plain":    func(img *image.Gray) *image.Gray { return img },
+		"blurred":  func(img *image.Gray) *image.Gray { return blur(img, 2) },
+		"noisy":    func(img *image.Gray) *image.Gray { return addNoise(img, 40) },
+		"inverted": invert,
+		"upside down": func(img *image.Gray) *image.Gray {
+			return rotate(img, 180)
+		},
+		"sideways": func(img *image.Gray) *image.Gray { return rotate(img, 90) },
+		"skewed": func(img *image.Gray) *image.Gray {
+			return addNoise(blur(rotate(img, 12), 1), 20)
+		},
+	}
+	for s, format := range codes {
+		img := symbolFor(t, s).Image(nil)
+		digits, addOn, _ := strings.Cut(s, " ")
+		for name, distort := range distortions {
+			barcodes, err := DecodeImage(distort(img))
+			if err != nil {
+				t.Errorf("%s %s: %s", name, s, err)
+				continue
+			}
+			b := barcodes[0]
+			if len(barcodes) != 1 || b.Format != format || b.Digits != digits || b.AddOn.String() != addOn {
+				t.Errorf("%s %s: got %v %s %s (%d barcodes)", name, s, b.Format, b.Digits, b.AddOn, len(barcodes))
+			}
+		}
+	}
+}
+
+func TestDecodeImageBounds(t *testing.T) {
+	// a UPC-A at 4 pixels per module, with its bars 22.85mm high
+	u, _ := Parse("036000291452")
+	barcodes, err := DecodeImage(u.Symbol().Image(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := barcodes[0].Bounds
+	if b.Min.X < 35 || b.Min.X > 37 || b.Max.X < 415 || b.Max.X > 417 {
+		t.Errorf("wrong horizontal bounds: got %v", b)
+	}
+	if b.Min.Y != 0 || b.Max.Y < 260 || b.Max.Y > 300 {
+		t.Errorf("wrong vertical bounds: got %v", b)
+	}
+	if c := barcodes[0].Confidence; c < 0.9 || c > 1 {
+		t.Errorf("wrong confidence: got %f", c)
+	}
+
+	if g := barcodes[0].Gtin(); !g.Equal(u.Gtin()) {
+		t.Errorf("wrong GTIN: got %s", g)
+	}
+	if e, ok := barcodes[0].Ean(); !ok || e.String() != "0036000291452" {
+		t.Errorf("wrong EAN: got %s", e)
+	}
+}
+
+func TestDecodeImageCodes(t *testing.T) {
+	e, _ := ParseUpcE("04252614")
+	barcodes, err := DecodeImage(e.Symbol().Image(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, ok := barcodes[0].Upc(); !ok || u != e.Upc() {
+		t.Errorf("wrong UPC: got %s", u)
+	}
+
+	e8, _ := ParseEan8("96385074")
+	barcodes, err = DecodeImage(e8.Symbol().Image(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := barcodes[0].Upc(); ok {
+		t.Errorf("EAN-8 returned as a UPC")
+	}
+	if g := barcodes[0].Gtin(); g.String() != "96385074" {
+		t.Errorf("wrong GTIN: got %s", g)
+	}
+}
+
+func TestDecodeImageAdjacent(t *testing.T) {
+	u, _ := Parse("036000291452")
+	e, _ := ParseEan("4006381333931")
+	opts := &RenderOptions{NoText: true}
+	top, bottom := u.Symbol().Image(opts), e.Symbol().Image(opts)
+	w, h := top.Bounds().Dx(), top.Bounds().Dy()
+
+	// side by side with their quiet zones touching, and one directly
+	// above the other, as on a sheet of labels
+	for name, at := range map[string]image.Point{
+		"side by side": image.Pt(w, 0),
+		"stacked":      image.Pt(0, h),
+	} {
+		r := bottom.Bounds().Add(at)
+		img := image.NewGray(r.Union(top.Bounds()))
+		fill(img, img.Bounds(), color.Gray{255})
+		draw.Draw(img, top.Bounds(), top, image.Point{}, draw.Src)
+		draw.Draw(img, r, bottom, image.Point{}, draw.Src)
+
+		barcodes, err := DecodeImage(img)
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+			continue
+		}
+		found := map[string]bool{}
+		for _, b := range barcodes {
+			found[b.Digits] = true
+		}
+		if len(barcodes) != 2 || !found["036000291452"] || !found["4006381333931"] {
+			t.Errorf("%s: expected both barcodes got %v", name, barcodes)
+		}
+	}
+}
+
+func TestDecodeImageNothing(t *testing.T) {
+	blank := image.NewGray(image.Rect(0, 0, 200, 100))
+	fill(blank, blank.Bounds(), color.Gray{255})
+	for _, img := range []*image.Gray{blank, addNoise(blank, 255)} {
+		if _, err := DecodeImage(img); err != ErrNoBarcode {
+			t.Errorf("expected ErrNoBarcode got %v", err)
+		}
+	}
+}
+
+func TestDecodeRuns(t *testing.T) {
+	codes := []string{"036000291452", "4006381333931", "96385074", "04252614", "036000291452 52495", "9780306406157 12"}
+	for _, s := range codes {
+		_, addOn, _ := strings.Cut(s, " ")
+		sym := symbolFor(t, s)
+		perfect := runsOf(sym)
+		for i := range perfect {
+			perfect[i] *= 17
+		}
+
+		// bars printed 0.3 modules too wide, jitter, and a scale growing
+		// by half across the barcode
+		rnd := rand.New(rand.NewSource(1))
+		distorted := runsOf(sym)
+		for i := range distorted {
+			if i%2 == 1 {
+				distorted[i] += 0.3
+			} else if distorted[i] > 0 {
+				distorted[i] -= 0.3
+			}
+			distorted[i] += rnd.Float64()*0.3 - 0.15
+			distorted[i] *= 2 + float64(i)/float64(len(distorted))
+		}
+
+		for name, runs := range map[string][]float64{
+			"perfect":   perfect,
+			"reversed":  reverse(perfect),
+			"distorted": distorted,
+		} {
+			b, err := DecodeRuns(runs)
+			if err != nil {
+				t.Errorf("%s %s: %s", name, s, err)
+				continue
+			}
+			if b.Format != sym.Format || b.Digits != sym.Digits || b.AddOn.String() != addOn {
+				t.Errorf("%s %s: got %v %s %s", name, s, b.Format, b.Digits, b.AddOn)
+			}
+			if name == "distorted" && (b.Confidence <= 0 || b.Confidence >= 1) {
+				t.Errorf("%s %s: wrong confidence: got %f", name, s, b.Confidence)
+			} else if name != "distorted" && b.Confidence != 1 {
+				t.Errorf("%s %s: wrong confidence: got %f", name, s, b.Confidence)
+			}
+		}
+	}
+
+	if _, err := DecodeRuns([]float64{10, 1, -1, 1}); err != ErrRunWidth {
+		t.Errorf("expected ErrRunWidth got %v", err)
+	}
+	for _, runs := range [][]float64{nil, {10, 1, 1, 1, 10}, {0, 1, 2, 3, 4, 3, 2, 1, 0}} {
+		if _, err := DecodeRuns(runs); err != ErrNoBarcode {
+			t.Errorf("%v: expected ErrNoBarcode got %v", runs, err)
+		}
+	}
+}
+
+// runsOf returns the widths in modules of the runs of bars and spaces
+// of a symbol, starting with its left quiet zone.
+func runsOf(s Symbol) []float64 {
+	runs := []float64{0}
+	for i, m := range s.Modules {
+		if i > 0 && m != s.Modules[i-1] {
+			runs = append(runs, 0)
+		}
+		runs[len(runs)-1]++
+	}
+	return runs
+}
+
+// blur returns the image blurred by averaging the pixels within r of
+// each.
+func blur(img *image.Gray, r int) *image.Gray {
+	b := img.Bounds()
+	out := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			var sum, n int
+			for dy := -r; dy <= r; dy++ {
+				for dx := -r; dx <= r; dx++ {
+					if p := image.Pt(x+dx, y+dy); p.In(b) {
+						sum += int(img.GrayAt(p.X, p.Y).Y)
+						n++
+					}
+				}
+			}
+			out.SetGray(x, y, color.Gray{uint8(sum / n)})
+		}
+	}
+	return out
+}
+
+// addNoise returns the image with random noise of up to the given
+// amount added to each pixel.
+func addNoise(img *image.Gray, amount int) *image.Gray {
+	rnd := rand.New(rand.NewSource(1))
+	out := image.NewGray(img.Bounds())
+	for i, v := range img.Pix {
+		n := int(v) + rnd.Intn(2*amount+1) - amount
+		if n < 0 {
+			n = 0
+		} else if n > 255 {
+			n = 255
+		}
+		out.Pix[i] = uint8(n)
+	}
+	return out
+}
+
+// invert returns the image with light and dark swapped.
+func invert(img *image.Gray) *image.Gray {
+	out := image.NewGray(img.Bounds())
+	for i, v := range img.Pix {
+		out.Pix[i] = 255 - v
+	}
+	return out
+}
+
+// rotate returns the image turned clockwise by an angle in degrees, on
+// a white background large enough to hold it.
+func rotate(img *image.Gray, degrees float64) *image.Gray {
+	b := img.Bounds()
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	w, h := float64(b.Dx()), float64(b.Dy())
+	ow := math.Round(math.Abs(w*cos) + math.Abs(h*sin))
+	oh := math.Round(math.Abs(w*sin) + math.Abs(h*cos))
+	out := image.NewGray(image.Rect(0, 0, int(ow), int(oh)))
+
+	at := func(x, y int) float64 {
+		if !image.Pt(x, y).In(b) {
+			return 255
+		}
+		return float64(img.GrayAt(x, y).Y)
+	}
+	for y := 0; y < int(oh); y++ {
+		for x := 0; x < int(ow); x++ {
+			// the centre of the pixel, turned back into the image
+			dx, dy := float64(x)+0.5-ow/2, float64(y)+0.5-oh/2
+			sx := dx*cos + dy*sin + w/2 - 0.5
+			sy := -dx*sin + dy*cos + h/2 - 0.5
+			x0, y0 := math.Floor(sx), math.Floor(sy)
+			fx, fy := sx-x0, sy-y0
+			ix, iy := int(x0), int(y0)
+			v := at(ix, iy)*(1-fx)*(1-fy) + at(ix+1, iy)*fx*(1-fy) +
+				at(ix, iy+1)*(1-fx)*fy + at(ix+1, iy+1)*fx*fy
+			out.SetGray(x, y, color.Gray{uint8(math.Round(v))})
+		}
+	}
+	return out
+}
@@ -0,0 +1,591 @@
+package upc
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultResolver is the GS1 global resolver, used for Digital Link
+// URIs when no domain is given.
+const DefaultResolver = "https://id.gs1.org"
+
+var ErrNotDigitalLink = errors.New("not a GS1 Digital Link URI with a GTIN")
+var ErrDigitalLinkPath = errors.New("GS1 Digital Link URI has misordered or repeated key qualifiers")
+var ErrDigitalLinkCompression = errors.New("GS1 Digital Link URI has invalid compressed data")
+
+// qualifiers are the AIs which follow a GTIN in the path of a Digital
+// Link URI, in order.  All other AIs are data attributes in the query.
+var qualifiers = []string{"22", "10", "21"}
+
+// DigitalLink returns the GS1 Digital Link URI of the GTIN on the
+// resolver at domain, e.g. https://id.gs1.org/01/00045496830434.  If
+// domain is empty, DefaultResolver is used.
+func (g Gtin) DigitalLink(domain string) string {
+	return resolverBase(domain) + "/01/" + g.Gtin14()
+}
+
+// DigitalLink returns the GS1 Digital Link URI of the elements, which
+// must include a GTIN, AI 01, on the resolver at domain.  Its key
+// qualifiers, AIs 22, 10 and 21, follow the GTIN in the path and the
+// other elements are in the query string, as in
+// https://id.gs1.org/01/00045496830434/10/LOT?17=251231.  If domain
+// is empty, DefaultResolver is used.  The following errors can be
+// returned in addition to those of Elements.Data:
+//
+//	ErrNotDigitalLink
+func (e Elements) DigitalLink(domain string) (string, error) {
+	if _, ok := e["01"].(Gtin); !ok {
+		return "", ErrNotDigitalLink
+	}
+	var path []string
+	for _, ai := range append([]string{"01"}, qualifiers...) {
+		if _, ok := e[ai]; !ok {
+			continue
+		}
+		data, err := e.data(ai)
+		if err != nil {
+			return "", err
+		}
+		path = append(path, ai, url.PathEscape(data))
+	}
+
+	var query []string
+	for _, ai := range e.attributes() {
+		data, err := e.data(ai)
+		if err != nil {
+			return "", err
+		}
+		query = append(query, ai+"="+url.QueryEscape(data))
+	}
+
+	s := resolverBase(domain) + "/" + strings.Join(path, "/")
+	if len(query) > 0 {
+		s += "?" + strings.Join(query, "&")
+	}
+	return s, nil
+}
+
+// CompressedDigitalLink returns the compressed form of the Digital
+// Link URI of the elements, which must include a GTIN, AI 01.  All
+// elements are packed into a single base64url path segment, as
+// described in the GS1 Digital Link compression standard, using the
+// longest optimised AI sequence the elements contain.  It returns the
+// same errors as DigitalLink.
+func (e Elements) CompressedDigitalLink(domain string) (string, error) {
+	if _, ok := e["01"].(Gtin); !ok {
+		return "", ErrNotDigitalLink
+	}
+	w := &bitWriter{}
+	done := make(map[string]bool)
+	if code, ok := e.optimisation(); ok {
+		w.write(uint64(code), 8)
+		for _, ai := range optimisedSequences[code] {
+			data, err := e.data(ai)
+			if err != nil {
+				return "", err
+			}
+			spec, _ := lookupAI(ai)
+			compressData(w, spec, data)
+			done[ai] = true
+		}
+	}
+	for _, ai := range append(append([]string{"01"}, qualifiers...), e.attributes()...) {
+		if _, ok := e[ai]; !ok || done[ai] {
+			continue
+		}
+		data, err := e.data(ai)
+		if err != nil {
+			return "", err
+		}
+		spec, _ := lookupAI(ai)
+		for _, b := range []byte(spec.ai) {
+			w.write(uint64(b-48), 4)
+		}
+		compressData(w, spec, data)
+	}
+	return resolverBase(domain) + "/" + w.base64(), nil
+}
+
+// optimisation returns the code of the longest optimised AI sequence
+// whose AIs are all in the elements, or false if there's none.
+func (e Elements) optimisation() (byte, bool) {
+	var best byte
+	for code, seq := range optimisedSequences {
+		n := len(optimisedSequences[best])
+		if len(seq) < n || len(seq) == n && code > best {
+			continue
+		}
+		found := true
+		for _, ai := range seq {
+			_, ok := e[ai]
+			found = found && ok
+		}
+		if found {
+			best = code
+		}
+	}
+	return best, best != 0
+}
+
+// attributes returns the AIs of the elements other than the GTIN and
+// its key qualifiers, in numeric order.
+func (e Elements) attributes() []string {
+	var ais []string
+	for ai := range e {
+		if ai != "01" && !isQualifier(ai) {
+			ais = append(ais, ai)
+		}
+	}
+	sort.Strings(ais)
+	return ais
+}
+
+// isQualifier returns true if ai is a key qualifier of a GTIN.
+func isQualifier(ai string) bool {
+	for _, q := range qualifiers {
+		if ai == q {
+			return true
+		}
+	}
+	return false
+}
+
+// resolverBase returns the scheme and domain of a Digital Link URI,
+// without a trailing slash.
+func resolverBase(domain string) string {
+	if domain == "" {
+		return DefaultResolver
+	}
+	if !strings.Contains(domain, "://") {
+		domain = "https://" + domain
+	}
+	return strings.TrimRight(domain, "/")
+}
+
+// ParseDigitalLink parses a GS1 Digital Link URI for a GTIN, on any
+// resolver domain, in its uncompressed or compressed form.  It returns
+// the GTIN and all the elements of the URI, including the GTIN as AI
+// 01.  Query parameters which aren't AIs, such as linkType, are
+// ignored.  The following errors can be returned in addition to those
+// of ParseElements:
+//
+//	ErrNotDigitalLink
+//	ErrDigitalLinkPath
+//	ErrDigitalLinkCompression
+func ParseDigitalLink(uri string) (Gtin, Elements, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return Gtin{}, nil, err
+	}
+	segments := strings.Split(strings.Trim(u.EscapedPath(), "/"), "/")
+
+	e := make(Elements)
+	start := -1
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "01" {
+			start = i
+			break
+		}
+	}
+	if start >= 0 {
+		if err := e.parsePath(segments[start:]); err != nil {
+			return Gtin{}, nil, err
+		}
+	} else if err := e.decompress(segments[len(segments)-1]); err != nil {
+		return Gtin{}, nil, err
+	}
+
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return Gtin{}, nil, err
+	}
+	for key, values := range query {
+		spec, ok := lookupAI(key)
+		if !ok || spec.ai != key {
+			continue
+		}
+		for _, v := range values {
+			if err := e.add(spec, v); err != nil {
+				return Gtin{}, nil, err
+			}
+		}
+	}
+
+	g, ok := e["01"].(Gtin)
+	if !ok {
+		return Gtin{}, nil, ErrNotDigitalLink
+	}
+	return g, e, nil
+}
+
+// parsePath parses the AI and value pairs of an uncompressed path,
+// starting with the GTIN.
+func (e Elements) parsePath(segments []string) error {
+	if len(segments)%2 != 0 {
+		return ErrDigitalLinkPath
+	}
+	next := 0 // index of the next allowed qualifier
+	for i := 0; i < len(segments); i += 2 {
+		ai := segments[i]
+		if i > 0 {
+			for next < len(qualifiers) && qualifiers[next] != ai {
+				next++
+			}
+			if next == len(qualifiers) {
+				return ErrDigitalLinkPath
+			}
+			next++
+		}
+
+		data, err := url.PathUnescape(segments[i+1])
+		if err != nil {
+			return err
+		}
+		spec, _ := lookupAI(ai)
+		if ai == "01" && len(data) < 14 {
+			data = strings.Repeat("0", 14-len(data)) + data
+		}
+		if err := e.add(spec, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// optimisedSequences maps the optimisation codes of a compressed
+// Digital Link to the AI sequences they stand for.  A code takes the
+// place of the AIs' nibbles, and is followed by the data of each AI in
+// turn.  Codes have a nibble of A to F, so they can't be mistaken for
+// the digits of an AI.
+var optimisedSequences = map[byte][]string{
+	0x0A: {"01", "22"},
+	0x0B: {"01", "10"},
+	0x0C: {"01", "21"},
+	0x0D: {"01", "17"},
+	0x0E: {"01", "7003"},
+	0x0F: {"01", "30"},
+	0x1A: {"01", "10", "21"},
+	0x1B: {"01", "10", "17"},
+	0x1C: {"01", "10", "21", "17"},
+}
+
+// escapeNibble begins a key and value which aren't a known AI, such as
+// a non-numeric query parameter, in a compressed Digital Link.
+const escapeNibble = 0xF
+
+// compressData appends the data of an element to the compressed bit
+// stream, component by component.
+func compressData(w *bitWriter, spec *aiSpec, data string) {
+	cs := spec.components()
+	for i, c := range cs {
+		n := c.max
+		if i == len(cs)-1 {
+			n = len(data)
+		}
+		part := data[:n]
+		data = data[n:]
+
+		switch {
+		case c.charset == 'N' && c.min == c.max:
+			w.writeDigits(part)
+		case c.charset == 'N':
+			w.write(uint64(len(part)), lengthBits(c.max))
+			w.writeDigits(part)
+		default:
+			compressAlphanumeric(w, part, c.max)
+		}
+	}
+}
+
+// compressAlphanumeric appends a string in its most compact encoding:
+// the encoding in 3 bits, the length, then the characters.
+func compressAlphanumeric(w *bitWriter, s string, max int) {
+	enc := alphanumericEncoding(s)
+	w.write(uint64(enc), 3)
+	w.write(uint64(len(s)), lengthBits(max))
+	if enc == encodingNumeric {
+		w.writeDigits(s)
+		return
+	}
+	for _, b := range []byte(s) {
+		w.write(uint64(strings.IndexByte(encodingAlphabets[enc], b)), encodingBits[enc])
+	}
+}
+
+// decompress parses the elements of a compressed path segment.  Each
+// element begins with the nibbles of its AI or with an optimisation
+// code.  Keys behind the escape nibble which aren't AIs are skipped,
+// like query parameters which aren't AIs.
+func (e Elements) decompress(segment string) error {
+	r, ok := newBitReader(segment)
+	if !ok {
+		return ErrDigitalLinkCompression
+	}
+	for r.remaining() >= 8 {
+		var ais []string
+		switch hi := r.read(4); {
+		case hi == escapeNibble:
+			key := r.readBase64(int(r.read(7)))
+			value, ok := r.readAlphanumeric(127)
+			if !ok || r.overrun {
+				return ErrDigitalLinkCompression
+			}
+			if spec, ok := lookupAI(key); ok && spec.ai == key {
+				if err := e.add(spec, value); err != nil {
+					return err
+				}
+			}
+			continue
+		case hi > 9:
+			return ErrDigitalLinkCompression
+		default:
+			lo := r.read(4)
+			if lo > 9 {
+				seq, ok := optimisedSequences[byte(hi<<4|lo)]
+				if !ok {
+					return ErrDigitalLinkCompression
+				}
+				ais = seq
+				break
+			}
+			ai := string(rune('0'+hi)) + string(rune('0'+lo))
+			for len(ai) < 4 && aiTable[ai] == nil {
+				d := r.read(4)
+				if d > 9 {
+					return ErrDigitalLinkCompression
+				}
+				ai += string(rune('0' + d))
+			}
+			ais = []string{ai}
+		}
+
+		for _, ai := range ais {
+			spec := aiTable[ai]
+			if spec == nil {
+				return ErrDigitalLinkCompression
+			}
+			data, ok := r.readData(spec)
+			if !ok || r.overrun {
+				return ErrDigitalLinkCompression
+			}
+			if err := e.add(spec, data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Encodings of alphanumeric data in a compressed Digital Link.
+const (
+	encodingNumeric = iota
+	encodingLowerHex
+	encodingUpperHex
+	encodingBase64
+	encodingAscii
+)
+
+// encodingAlphabets maps the values of each encoding, other than
+// numeric, to characters.
+var encodingAlphabets = []string{
+	encodingLowerHex: "0123456789abcdef",
+	encodingUpperHex: "0123456789ABCDEF",
+	encodingBase64:   "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_",
+	encodingAscii:    asciiAlphabet(),
+}
+
+// encodingBits is the number of bits per character of each encoding.
+var encodingBits = []int{0, 4, 4, 6, 7}
+
+// asciiAlphabet returns the 128 characters of 7-bit ASCII in order.
+func asciiAlphabet() string {
+	b := make([]byte, 128)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return string(b)
+}
+
+// alphanumericEncoding returns the most compact encoding of s.
+func alphanumericEncoding(s string) int {
+	for _, enc := range []int{encodingLowerHex, encodingUpperHex, encodingBase64} {
+		ok := true
+		for _, b := range []byte(s) {
+			ok = ok && strings.IndexByte(encodingAlphabets[enc], b) >= 0
+		}
+		if ok && enc == encodingLowerHex && strings.Trim(s, "0123456789") == "" {
+			return encodingNumeric
+		}
+		if ok {
+			return enc
+		}
+	}
+	return encodingAscii
+}
+
+// lengthBits returns the number of bits needed for the length of a
+// component of at most max characters.
+func lengthBits(max int) int {
+	return int(math.Ceil(math.Log2(float64(max + 1))))
+}
+
+// digitBits returns the number of bits needed for n decimal digits.
+func digitBits(n int) int {
+	return int(math.Ceil(float64(n) * math.Log2(10)))
+}
+
+// bitWriter accumulates a stream of bits, most significant first.
+type bitWriter struct {
+	bits []byte // one bit per byte
+}
+
+// write appends the low n bits of v.
+func (w *bitWriter) write(v uint64, n int) {
+	for i := n - 1; i >= 0; i-- {
+		w.bits = append(w.bits, byte(v>>uint(i)&1))
+	}
+}
+
+// writeDigits appends a string of digits as a binary number.
+func (w *bitWriter) writeDigits(s string) {
+	n, _ := new(big.Int).SetString("0"+s, 10)
+	for i := digitBits(len(s)) - 1; i >= 0; i-- {
+		w.bits = append(w.bits, byte(n.Bit(i)))
+	}
+}
+
+// base64 returns the bits, padded with zeros to a multiple of 6, in
+// base64url without padding.
+func (w *bitWriter) base64() string {
+	var s []byte
+	for i := 0; i < len(w.bits); i += 6 {
+		var v byte
+		for j := i; j < i+6; j++ {
+			v <<= 1
+			if j < len(w.bits) {
+				v |= w.bits[j]
+			}
+		}
+		s = append(s, encodingAlphabets[encodingBase64][v])
+	}
+	return string(s)
+}
+
+// bitReader consumes a stream of bits decoded from base64url.  Reading
+// past the end sets overrun and returns zeros.
+type bitReader struct {
+	bits    []byte // one bit per byte
+	overrun bool
+}
+
+// newBitReader decodes a base64url string without padding.  It returns
+// false if s has other characters.
+func newBitReader(s string) (*bitReader, bool) {
+	r := &bitReader{}
+	for _, b := range []byte(s) {
+		v := strings.IndexByte(encodingAlphabets[encodingBase64], b)
+		if v < 0 {
+			return nil, false
+		}
+		for i := 5; i >= 0; i-- {
+			r.bits = append(r.bits, byte(v>>uint(i)&1))
+		}
+	}
+	return r, true
+}
+
+// remaining returns the number of unread bits.
+func (r *bitReader) remaining() int {
+	return len(r.bits)
+}
+
+// read returns the next n bits.
+func (r *bitReader) read(n int) uint64 {
+	var v uint64
+	for i := 0; i < n; i++ {
+		v = v<<1 | uint64(r.bit())
+	}
+	return v
+}
+
+// readDigits returns the next binary number as n decimal digits.
+func (r *bitReader) readDigits(n int) string {
+	v := new(big.Int)
+	for i := 0; i < digitBits(n); i++ {
+		v.Lsh(v, 1)
+		v.SetBit(v, 0, r.bit())
+	}
+	s := v.String()
+	if len(s) > n {
+		r.overrun = true
+		return strings.Repeat("0", n)
+	}
+	return strings.Repeat("0", n-len(s)) + s
+}
+
+// readData returns the data of an element, component by component.
+// It returns false if the data is malformed.
+func (r *bitReader) readData(spec *aiSpec) (string, bool) {
+	var data string
+	for _, c := range spec.components() {
+		switch {
+		case c.charset == 'N' && c.min == c.max:
+			data += r.readDigits(c.max)
+		case c.charset == 'N':
+			data += r.readDigits(int(r.read(lengthBits(c.max))))
+		default:
+			s, ok := r.readAlphanumeric(c.max)
+			if !ok {
+				return "", false
+			}
+			data += s
+		}
+	}
+	return data, true
+}
+
+// readAlphanumeric returns a string written by compressAlphanumeric.
+// It returns false if the encoding or a character is invalid.
+func (r *bitReader) readAlphanumeric(max int) (string, bool) {
+	enc := int(r.read(3))
+	n := int(r.read(lengthBits(max)))
+	if enc == encodingNumeric {
+		return r.readDigits(n), true
+	}
+	if enc >= len(encodingAlphabets) {
+		return "", false
+	}
+	var s []byte
+	for i := 0; i < n; i++ {
+		k := int(r.read(encodingBits[enc]))
+		if k >= len(encodingAlphabets[enc]) {
+			return "", false
+		}
+		s = append(s, encodingAlphabets[enc][k])
+	}
+	return string(s), true
+}
+
+// readBase64 returns the next n characters of 6 bits each.
+func (r *bitReader) readBase64(n int) string {
+	s := make([]byte, n)
+	for i := range s {
+		s[i] = encodingAlphabets[encodingBase64][r.read(6)]
+	}
+	return string(s)
+}
+
+// bit returns the next bit.
+func (r *bitReader) bit() uint {
+	if len(r.bits) == 0 {
+		r.overrun = true
+		return 0
+	}
+	bit := r.bits[0]
+	r.bits = r.bits[1:]
+	return uint(bit)
+}
@@ -0,0 +1,153 @@
+package upc
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDigitalLink(t *testing.T) {
+	defer fixedNow()()
+	g := mustGtin("045496830434")
+	if s := g.DigitalLink(""); s != "https://id.gs1.org/01/00045496830434" {
+		t.Errorf("wrong GTIN link: got %s", s)
+	}
+
+	e := Elements{
+		"01":   mustGtin("00045496830434"),
+		"10":   "LOT/1",
+		"21":   "50%",
+		"17":   AIDate{Time: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+		"3103": Decimal{1250, 3},
+	}
+	s, err := e.DigitalLink("example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "https://example.com/01/00045496830434/10/LOT%2F1/21/50%25?17=251231&3103=001250"
+	if s != want {
+		t.Errorf("wrong link\n got: %s\nwant: %s", s, want)
+	}
+
+	g2, e2, err := ParseDigitalLink(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.Equal(g) || !reflect.DeepEqual(e2, e) {
+		t.Errorf("wrong round trip: got %s %#v", g2, e2)
+	}
+}
+
+func TestParseDigitalLink(t *testing.T) {
+	defer fixedNow()()
+	links := map[string]Elements{
+		"https://id.gs1.org/01/00045496830434/10/LOT?17=251231": {
+			"01": mustGtin("00045496830434"),
+			"10": "LOT",
+			"17": AIDate{Time: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+		},
+		"https://brand.example/products/01/045496830434/22/2A/21/7?linkType=gs1:pip": {
+			"01": mustGtin("00045496830434"),
+			"22": "2A",
+			"21": "7",
+		},
+	}
+	for s, want := range links {
+		g, e, err := ParseDigitalLink(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if !reflect.DeepEqual(e, want) || !g.Equal(want["01"].(Gtin)) {
+			t.Errorf("%s: wrong elements\n got: %#v\nwant: %#v\n", s, e, want)
+		}
+	}
+}
+
+func TestCompressedDigitalLink(t *testing.T) {
+	defer fixedNow()()
+	e := Elements{
+		"01":   mustGtin("00045496830434"),
+		"10":   "abc123",
+		"21":   "Serial-7",
+		"17":   AIDate{Time: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+		"3922": Decimal{1999, 2},
+		"8200": "https://example.com/x?y",
+	}
+	s, err := e.CompressedDigitalLink("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) >= len("https://id.gs1.org/")+len("0100045496830434")+len("10abc123")+len("21Serial-7")+len("17251231")+len("39221999")+len("8200https://example.com/x?y") {
+		t.Errorf("compressed link isn't compressed: %s", s)
+	}
+	_, got, err := ParseDigitalLink(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, e) {
+		t.Errorf("wrong round trip\n got: %#v\nwant: %#v\n", got, e)
+	}
+
+	// the GTIN alone: 8 bits of AI and 47 of data
+	s, _ = Elements{"01": mustGtin("00045496830434")}.CompressedDigitalLink("https://example.com")
+	if len(s) != len("https://example.com/")+10 {
+		t.Errorf("wrong compressed length: %s", s)
+	}
+}
+
+// compressed links written out bit by bit
+var compressedLinks = map[string]Elements{
+	// optimisation code 0B for AIs 01 and 10, the 47 bits of the GTIN,
+	// then ABC as upper case hex: encoding 2, 5 bits of length and 4
+	// bits per character
+	"https://id.gs1.org/CwAVL6UTxIdXg": {
+		"01": mustGtin("00045496830434"),
+		"10": "ABC",
+	},
+	// escapes for linkType=gs1:pip in 7-bit ASCII and 17=251231 as
+	// digits, then AI 01
+	"https://id.gs1.org/8RLFPInlS9A-fmxdcNPDwWvYDHqr4CACpfSieI": {
+		"01": mustGtin("00045496830434"),
+		"17": AIDate{Time: time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)},
+	},
+}
+
+func TestDecompressDigitalLink(t *testing.T) {
+	defer fixedNow()()
+	for s, want := range compressedLinks {
+		_, e, err := ParseDigitalLink(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if !reflect.DeepEqual(e, want) {
+			t.Errorf("%s: wrong elements\n got: %#v\nwant: %#v\n", s, e, want)
+		}
+	}
+
+	e := compressedLinks["https://id.gs1.org/CwAVL6UTxIdXg"]
+	if s, _ := e.CompressedDigitalLink(""); s != "https://id.gs1.org/CwAVL6UTxIdXg" {
+		t.Errorf("wrong optimised link: got %s", s)
+	}
+}
+
+func TestDigitalLinkWrong(t *testing.T) {
+	errs := map[string]error{
+		"https://id.gs1.org/":                            ErrNotDigitalLink,
+		"https://id.gs1.org/01/00045496830434/21/1/10/A": ErrDigitalLinkPath,
+		"https://id.gs1.org/01/00045496830434/10/A/10/A": ErrDigitalLinkPath,
+		"https://id.gs1.org/01/00045496830434/17/251231": ErrDigitalLinkPath,
+		"https://id.gs1.org/01/00045496830434/10":        ErrDigitalLinkPath,
+		"https://id.gs1.org/01/00045496830435":           ErrAICheckDigit,
+		"https://id.gs1.org/01/00045496830434?17=251301": ErrAIDate,
+		"https://id.gs1.org/AQAVjx3Q5~":                  ErrDigitalLinkCompression,
+		"https://id.gs1.org/F":                           ErrNotDigitalLink,
+		"https://id.gs1.org/Fg":                          ErrDigitalLinkCompression,
+		"https://id.gs1.org/Kg":                          ErrDigitalLinkCompression,
+	}
+	for s, want := range errs {
+		if _, _, err := ParseDigitalLink(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+	if _, err := (Elements{"10": "A"}).DigitalLink(""); err != ErrNotDigitalLink {
+		t.Errorf("expected ErrNotDigitalLink got %q", err)
+	}
+}
@@ -12,18 +12,27 @@ type Ean int64
 var ErrEanTooShort = errors.New("EAN is too short (must be 12 digits)")
 var ErrEanTooLong = errors.New("EAN is too long (must be 13 digits)")
 var ErrEanInvalidCheckDigit = errors.New("EAN has an invalid check digit")
+var ErrEanAddOn = errors.New("EAN is followed by an add-on (use ParseEanWithAddOn)")
 
-// Parse parses a string into a Ean value.  The following errors can
-// be returned in addition to integer parsing errors:
+// Parse parses a string into a Ean value.  A valid EAN followed by an
+// add-on is rejected with ErrEanAddOn; use ParseEanWithAddOn for
+// those.  The following errors can be returned in addition to integer
+// parsing errors:
 //
 //     ErrEanTooShort
 //     ErrEanTooLong
+//     ErrEanAddOn
 //     ErrEanInvalidCheckDigit
 func ParseEan(s string) (Ean, error) {
 	if len(s) < 12 {
 		return 0, ErrEanTooShort
 	}
 	if len(s) > 13 {
+		if _, _, ok := splitAddOn(s, 12, 13); ok {
+			if _, err := ParseEanWithAddOn(s); err == nil {
+				return 0, ErrEanAddOn
+			}
+		}
 		return 0, ErrEanTooLong
 	}
 
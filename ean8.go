@@ -0,0 +1,69 @@
+package upc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Ean8 represents an 8-digit European Article Number, used on
+// packages too small for an EAN-13.  To reduce memory consumption,
+// it's stored as a 64-bit integer without the check digit.
+type Ean8 int64
+
+var ErrEan8TooShort = errors.New("EAN-8 is too short (must be 8 digits)")
+var ErrEan8TooLong = errors.New("EAN-8 is too long (must be 8 digits)")
+var ErrEan8InvalidCheckDigit = errors.New("EAN-8 has an invalid check digit")
+
+// ParseEan8 parses a string into an Ean8 value.  The following errors
+// can be returned in addition to integer parsing errors:
+//
+//	ErrEan8TooShort
+//	ErrEan8TooLong
+//	ErrEan8InvalidCheckDigit
+func ParseEan8(s string) (Ean8, error) {
+	if len(s) < 8 {
+		return 0, ErrEan8TooShort
+	}
+	if len(s) > 8 {
+		return 0, ErrEan8TooLong
+	}
+
+	var n int64
+	var check int
+	for i, b := range []byte(s) {
+		if b < 48 || b > 57 {
+			return 0, fmt.Errorf("Invalid EAN-8 digit: %c", b)
+		}
+		if i == 7 {
+			check = int(b - 48)
+		} else {
+			n *= 10
+			n += int64(b - 48)
+		}
+	}
+	e := Ean8(n)
+	if e.CheckDigit() != check {
+		return 0, ErrEan8InvalidCheckDigit
+	}
+
+	return e, nil
+}
+
+// String returns the standard, 8-digit string representation of this
+// EAN-8.
+func (e Ean8) String() string {
+	return fmt.Sprintf("%07d%d", int64(e), e.CheckDigit())
+}
+
+// CheckDigit returns the check digit that should be used as the 8th
+// digit of the EAN-8.  The weighting is the same as for an EAN-13,
+// starting with 3 on the rightmost digit.
+func (e Ean8) CheckDigit() int {
+	return Ean(e).CheckDigit()
+}
+
+// Gtin14 returns the 14-digit GTIN representation of this EAN-8,
+// padded on the left with zeros.
+func (e Ean8) Gtin14() string {
+	return fmt.Sprintf("%013d%d", int64(e), e.CheckDigit())
+}
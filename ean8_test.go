@@ -0,0 +1,54 @@
+package upc
+
+import "testing"
+
+var ean8Tests = map[string]string{
+	"96385074": "00000096385074",
+	"55123457": "00000055123457",
+	"00000017": "00000000000017",
+}
+
+func TestEan8(t *testing.T) {
+	for s, gtin := range ean8Tests {
+		e, err := ParseEan8(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if e.String() != s {
+			t.Errorf("%s: wrong string: got %s", s, e)
+		}
+		if int(s[7]-'0') != e.CheckDigit() {
+			t.Errorf("%s: wrong check digit: got %d", s, e.CheckDigit())
+		}
+		if e.Gtin14() != gtin {
+			t.Errorf("%s: wrong GTIN-14: got %s want %s", s, e.Gtin14(), gtin)
+		}
+	}
+}
+
+func TestEan8Wrong(t *testing.T) {
+	errs := map[string]error{
+		"":              ErrEan8TooShort,
+		"9638507":       ErrEan8TooShort,
+		"963850740":     ErrEan8TooLong,
+		"0045496830434": ErrEan8TooLong,
+		"96385075":      ErrEan8InvalidCheckDigit,
+	}
+	for s, want := range errs {
+		if _, err := ParseEan8(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+
+	digit := []string{
+		"x6385074",
+		"9638507x",
+		"963-5074",
+	}
+	for _, s := range digit {
+		if _, err := ParseEan8(s); err == nil {
+			t.Errorf("%s: expected an error got none", s)
+		}
+	}
+}
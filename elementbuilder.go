@@ -0,0 +1,293 @@
+package upc
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrAIRequires = errors.New("GS1 element string lacks an application identifier required by another")
+var ErrAIExcludes = errors.New("GS1 element string combines application identifiers which can't appear together")
+
+// ElementBuilder assembles a GS1 element string from typed values.
+// Each method adds an element and returns the builder so calls can be
+// chained.  The first error sticks: later additions are ignored and
+// the error is returned by Elements, Bracketed and Raw.
+type ElementBuilder struct {
+	e   Elements
+	err error
+}
+
+// NewElementBuilder returns an empty ElementBuilder.
+func NewElementBuilder() *ElementBuilder {
+	return &ElementBuilder{e: make(Elements)}
+}
+
+// Add adds an element of any AI.  v must have the type documented on
+// Elements for the AI, and is stored as it is once its data has been
+// validated, so a date keeps its century.
+func (b *ElementBuilder) Add(ai string, v interface{}) *ElementBuilder {
+	if b.err != nil {
+		return b
+	}
+	spec, ok := lookupAI(ai)
+	if !ok || spec.ai != ai {
+		b.err = ErrUnknownAI
+		return b
+	}
+	if _, err := spec.format(v); err != nil {
+		b.err = err
+		return b
+	}
+	b.err = b.e.put(ai, v)
+	return b
+}
+
+// Sscc adds the serial shipping container code, AI 00.
+func (b *ElementBuilder) Sscc(sscc Sscc) *ElementBuilder {
+	return b.Add("00", sscc)
+}
+
+// Gtin adds the GTIN of the trade item, AI 01.
+func (b *ElementBuilder) Gtin(g Gtin) *ElementBuilder {
+	return b.Add("01", g)
+}
+
+// Content adds the GTIN of the trade items contained in a logistic
+// unit, AI 02.  It requires a Count.
+func (b *ElementBuilder) Content(g Gtin) *ElementBuilder {
+	return b.Add("02", g)
+}
+
+// Batch adds the batch or lot number, AI 10.
+func (b *ElementBuilder) Batch(lot string) *ElementBuilder {
+	return b.Add("10", lot)
+}
+
+// ProductionDate adds the production date, AI 11.
+func (b *ElementBuilder) ProductionDate(t time.Time) *ElementBuilder {
+	return b.Add("11", AIDate{Time: t})
+}
+
+// BestBefore adds the best before date, AI 15.
+func (b *ElementBuilder) BestBefore(t time.Time) *ElementBuilder {
+	return b.Add("15", AIDate{Time: t})
+}
+
+// Expiry adds the expiration date, AI 17.
+func (b *ElementBuilder) Expiry(t time.Time) *ElementBuilder {
+	return b.Add("17", AIDate{Time: t})
+}
+
+// Serial adds the serial number, AI 21.
+func (b *ElementBuilder) Serial(serial string) *ElementBuilder {
+	return b.Add("21", serial)
+}
+
+// Quantity adds the count of a variable measure trade item, AI 30.
+func (b *ElementBuilder) Quantity(n int) *ElementBuilder {
+	return b.Add("30", n)
+}
+
+// Count adds the count of trade items contained in a logistic unit,
+// AI 37.
+func (b *ElementBuilder) Count(n int) *ElementBuilder {
+	return b.Add("37", n)
+}
+
+// Err returns the first error encountered while adding elements.
+func (b *ElementBuilder) Err() error {
+	return b.err
+}
+
+// Elements returns a copy of the elements added so far, after
+// checking that they may appear together.
+func (b *ElementBuilder) Elements() (Elements, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if err := b.e.Validate(); err != nil {
+		return nil, err
+	}
+	e := make(Elements, len(b.e))
+	for ai, v := range b.e {
+		e[ai] = v
+	}
+	return e, nil
+}
+
+// Bracketed returns the human readable element string, as printed
+// under a GS1-128 symbol.  See Elements.Bracketed.
+func (b *ElementBuilder) Bracketed() (string, error) {
+	e, err := b.Elements()
+	if err != nil {
+		return "", err
+	}
+	return e.Bracketed()
+}
+
+// Raw returns the element string to encode in a symbol.  See
+// Elements.Raw.
+func (b *ElementBuilder) Raw() (string, error) {
+	e, err := b.Elements()
+	if err != nil {
+		return "", err
+	}
+	return e.Raw()
+}
+
+// Bracketed returns the element string with each AI in parentheses,
+// e.g. "(01)00045496830434(17)251231(10)ABC".  Elements are in the
+// order used by Raw.
+func (e Elements) Bracketed() (string, error) {
+	var s []string
+	for _, ai := range e.order() {
+		data, err := e.data(ai)
+		if err != nil {
+			return "", err
+		}
+		s = append(s, "("+ai+")"+data)
+	}
+	return strings.Join(s, ""), nil
+}
+
+// Raw returns the element string as it's encoded in a symbol.
+// Elements whose length is predefined come first.  An element of any
+// other AI is followed by an ASCII group separator (FNC1) unless it's
+// last.  The leading FNC1 and symbology identifier aren't included.
+func (e Elements) Raw() (string, error) {
+	var s []string
+	order := e.order()
+	for i, ai := range order {
+		data, err := e.data(ai)
+		if err != nil {
+			return "", err
+		}
+		s = append(s, ai+data)
+		if predefinedLength(ai) == 0 && i < len(order)-1 {
+			s = append(s, string(groupSeparator))
+		}
+	}
+	return strings.Join(s, ""), nil
+}
+
+// data returns the data of an element, or the reason its value is
+// invalid.
+func (e Elements) data(ai string) (string, error) {
+	spec, ok := lookupAI(ai)
+	if !ok || spec.ai != ai {
+		return "", ErrUnknownAI
+	}
+	return spec.format(e[ai])
+}
+
+// order returns the AIs in the order they're encoded: those with a
+// predefined length, then the rest, each in numeric order.
+func (e Elements) order() []string {
+	var fixed, variable []string
+	for ai := range e {
+		if predefinedLength(ai) > 0 {
+			fixed = append(fixed, ai)
+		} else {
+			variable = append(variable, ai)
+		}
+	}
+	sort.Strings(fixed)
+	sort.Strings(variable)
+	return append(fixed, variable...)
+}
+
+// Validate checks that the elements may appear together according to
+// the GS1 General Specifications: that each AI which requires another
+// has it, and that no mutually exclusive AIs are combined.  The
+// following errors can be returned:
+//
+//	ErrAIRequires
+//	ErrAIExcludes
+func (e Elements) Validate() error {
+	for _, x := range aiExclusions {
+		if e.has(x[0]) && e.has(x[1]) {
+			return ErrAIExcludes
+		}
+	}
+
+	// only one decimal point position per measure
+	seen := make(map[string]bool)
+	for ai := range e {
+		if len(ai) == 4 && ai[0] == '3' {
+			if seen[ai[:3]] {
+				return ErrAIExcludes
+			}
+			seen[ai[:3]] = true
+		}
+	}
+
+	for _, r := range aiRequirements {
+		for _, ai := range r.ais {
+			if !e.has(ai) {
+				continue
+			}
+			ok := false
+			for _, need := range r.need {
+				ok = ok || e.has(need)
+			}
+			if !ok {
+				return ErrAIRequires
+			}
+		}
+	}
+	return nil
+}
+
+// has returns true if an element's AI begins with prefix.
+func (e Elements) has(prefix string) bool {
+	for ai := range e {
+		if strings.HasPrefix(ai, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// aiExclusions are the pairs of AI prefixes which can't appear in the
+// same element string.
+var aiExclusions = [][2]string{
+	{"01", "02"},
+	{"01", "37"},
+	{"01", "8006"},
+	{"01", "8026"},
+	{"02", "8006"},
+	{"02", "8026"},
+	{"21", "235"},
+	{"390", "391"},
+	{"392", "393"},
+	{"394", "8111"},
+	{"420", "421"},
+	{"422", "426"},
+	{"423", "426"},
+	{"424", "426"},
+	{"425", "426"},
+}
+
+// aiRequirements lists AI prefixes which need at least one of another
+// set of AIs in the same element string.
+var aiRequirements = []struct {
+	ais  []string
+	need []string
+}{
+	{[]string{"02"}, []string{"37"}},
+	{[]string{"37"}, []string{"02", "8026"}},
+	{[]string{"21", "235", "250", "251"}, []string{"01", "03", "8006"}},
+	{
+		[]string{"10", "11", "13", "15", "16", "17", "20", "22", "240", "241", "242", "243",
+			"30", "31", "32", "392", "393", "395",
+			"7001", "7002", "7003", "7004", "7005", "7006", "7007", "7008", "7009", "7010",
+			"7011", "7020", "7021", "7022", "7023", "8001", "8005", "8008"},
+		[]string{"01", "02", "03", "8006", "8026"},
+	},
+	{[]string{"33", "34", "35", "36"}, []string{"00", "01", "02", "03", "8006", "8026"}},
+	{[]string{"254"}, []string{"414"}},
+	{[]string{"394", "8111"}, []string{"255"}},
+	{[]string{"43"}, []string{"00"}},
+}
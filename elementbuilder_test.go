@@ -0,0 +1,122 @@
+package upc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestElementBuilder(t *testing.T) {
+	defer fixedNow()()
+	b := NewElementBuilder().
+		Gtin(mustGtin("045496830434")).
+		Serial("12345").
+		Batch("ABC").
+		Expiry(time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC)).
+		Add("3103", Decimal{1250, 3})
+
+	s, err := b.Bracketed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "(01)00045496830434(17)251231(3103)001250(10)ABC(21)12345"; s != want {
+		t.Errorf("wrong bracketed string\n got: %s\nwant: %s", s, want)
+	}
+
+	raw, err := b.Raw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "0100045496830434172512313103001250" + "10ABC\x1d" + "2112345"; raw != want {
+		t.Errorf("wrong raw string\n got: %q\nwant: %q", raw, want)
+	}
+
+	// round trip through the parser
+	e, err := ParseElements(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := e.Bracketed(); again != s {
+		t.Errorf("wrong round trip: got %s", again)
+	}
+}
+
+func TestElementBuilderElements(t *testing.T) {
+	defer fixedNow()()
+	// outside the century window of a date parsed in 2025
+	expiry := time.Date(2080, 1, 31, 0, 0, 0, 0, time.UTC)
+	b := NewElementBuilder().Gtin(mustGtin("045496830434")).Expiry(expiry)
+	e, err := b.Elements()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := e["17"].(AIDate); !got.Time.Equal(expiry) || got.LastDayOfMonth {
+		t.Errorf("wrong expiry: got %v", got)
+	}
+
+	// the builder's elements can't be changed through the copy
+	e["10"] = "ABC"
+	if again, _ := b.Elements(); len(again) != 2 {
+		t.Errorf("builder changed through its elements: got %v", again)
+	}
+}
+
+func TestElementBuilderPallet(t *testing.T) {
+	raw, err := NewElementBuilder().
+		Count(40).
+		Content(mustGtin("0045496830434")).
+		Sscc(10614141123456789).
+		Raw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "00106141411234567897" + "0200045496830434" + "3740"; raw != want {
+		t.Errorf("wrong raw string\n got: %q\nwant: %q", raw, want)
+	}
+}
+
+func TestElementBuilderWrong(t *testing.T) {
+	gtin := mustGtin("00045496830434")
+	builders := map[*ElementBuilder]error{
+		NewElementBuilder().Gtin(gtin).Content(gtin).Count(1):                                ErrAIExcludes,
+		NewElementBuilder().Gtin(gtin).Count(1):                                              ErrAIExcludes,
+		NewElementBuilder().Gtin(gtin).Add("3102", Decimal{1, 2}).Add("3103", Decimal{1, 3}): ErrAIExcludes,
+		NewElementBuilder().Content(gtin):                                                    ErrAIRequires,
+		NewElementBuilder().Sscc(10614141123456789).Batch("ABC"):                             ErrAIRequires,
+		NewElementBuilder().Sscc(10614141123456789).Serial("1"):                              ErrAIRequires,
+		NewElementBuilder().Add("89", "x"):                                                   ErrUnknownAI,
+		NewElementBuilder().Batch("ABCDEFGHIJKLMNOPQRSTU").Gtin(gtin):                        ErrAIDataLength,
+		NewElementBuilder().Gtin(gtin).Batch("A").Batch("B"):                                 ErrAIDuplicate,
+	}
+	for b, want := range builders {
+		if _, err := b.Raw(); err != want {
+			t.Errorf("expected %q got %q", want, err)
+		}
+	}
+
+	b := NewElementBuilder().Add("00", "106141411234567897")
+	if b.Err() == nil {
+		t.Errorf("expected a type error got none")
+	}
+
+	b = NewElementBuilder().Add("17", "251231")
+	if b.Err() == nil {
+		t.Errorf("expected a type error got none")
+	}
+	if _, err := b.Bracketed(); err != b.Err() {
+		t.Errorf("expected the first error got %q", err)
+	}
+}
+
+func TestElementsValidate(t *testing.T) {
+	e, err := ParseElements("(00)106141411234567897(02)00045496830434(10)ABC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Validate(); err != ErrAIRequires {
+		t.Errorf("expected ErrAIRequires got %q", err)
+	}
+	e["37"] = 12
+	if err := e.Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,213 @@
+package upc
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// GcpTable maps prefixes of GTIN-13s to the length of the GS1 company
+// prefix of the numbers beginning with them, as published by GS1 in
+// its "gcpprefixformatlist".  A length of 0 means numbers beginning
+// with the prefix have no company prefix, as for restricted circulation
+// numbers and coupons.
+type GcpTable struct {
+	lengths map[string]int
+}
+
+var ErrGcpTable = errors.New("malformed GS1 company prefix length table")
+
+// gcpEntry is an entry of the XML or JSON format of the table.
+type gcpEntry struct {
+	Prefix    string          `xml:"prefix,attr" json:"prefix"`
+	GcpLength json.RawMessage `xml:"-" json:"gcpLength"`
+	XmlLength string          `xml:"gcpLength,attr" json:"-"`
+}
+
+// LoadGcpTable reads a GS1 company prefix length table in the XML or
+// JSON format of GS1's gcpprefixformatlist.  The following errors can
+// be returned in addition to read and decoding errors:
+//
+//	ErrGcpTable
+func LoadGcpTable(r io.Reader) (*GcpTable, error) {
+	br := bufio.NewReader(r)
+	var entries []gcpEntry
+	first, err := firstNonSpace(br)
+	if err != nil {
+		return nil, err
+	}
+	if first == '<' {
+		var list struct {
+			Entries []gcpEntry `xml:"entry"`
+		}
+		if err := xml.NewDecoder(br).Decode(&list); err != nil {
+			return nil, err
+		}
+		entries = list.Entries
+	} else {
+		var list struct {
+			List struct {
+				Entries []gcpEntry `json:"entry"`
+			} `json:"GCPPrefixFormatList"`
+		}
+		if err := json.NewDecoder(br).Decode(&list); err != nil {
+			return nil, err
+		}
+		entries = list.List.Entries
+	}
+
+	t := &GcpTable{lengths: make(map[string]int)}
+	for _, e := range entries {
+		length := e.XmlLength
+		if e.GcpLength != nil {
+			length = strings.Trim(string(e.GcpLength), `"`)
+		}
+		n, err := strconv.Atoi(length)
+		if err != nil || !validGcpEntry(e.Prefix, n) {
+			return nil, ErrGcpTable
+		}
+		t.lengths[e.Prefix] = n
+	}
+	if len(t.lengths) == 0 {
+		return nil, ErrGcpTable
+	}
+	return t, nil
+}
+
+// firstNonSpace returns the first byte of r which isn't white space,
+// without consuming it.
+func firstNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n', 0xef, 0xbb, 0xbf: // and a UTF-8 BOM
+			r.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+// validGcpEntry returns true if prefix is 1 to 12 digits and n is 0
+// or a company prefix length at least as long as the prefix.
+func validGcpEntry(prefix string, n int) bool {
+	if len(prefix) < 1 || len(prefix) > 12 {
+		return false
+	}
+	for _, b := range []byte(prefix) {
+		if b < 48 || b > 57 {
+			return false
+		}
+	}
+	return n == 0 || (n >= len(prefix) && n >= 4 && n <= 12)
+}
+
+// Length returns the length of the company prefix of a GTIN-13, given
+// as 13 digits, by finding the longest matching prefix in the table.
+// The second return value is false if no prefix matches.
+func (t *GcpTable) Length(gtin13 string) (int, bool) {
+	for n := len(gtin13); n > 0; n-- {
+		if length, ok := t.lengths[gtin13[:n]]; ok {
+			return length, true
+		}
+	}
+	return 0, false
+}
+
+//go:embed gcpprefixes.json
+var gcpPrefixData string
+
+var defaultGcpOnce sync.Once
+var defaultGcp *GcpTable
+
+// DefaultGcpTable returns the table embedded in the package.  It holds
+// the prefixes which GS1 reserves for numbers without a company
+// prefix, and the prefixes of each GS1 member organization with the
+// length of the company prefixes it has mostly issued: 7 digits, or 6
+// for a U.P.C. company prefix, except for China's 692-699, where it's
+// 8.  Member organizations also issue longer company prefixes, so load
+// GS1's full list with LoadGcpTable to split every number exactly.
+func DefaultGcpTable() *GcpTable {
+	defaultGcpOnce.Do(func() {
+		t, err := LoadGcpTable(strings.NewReader(gcpPrefixData))
+		if err != nil {
+			panic(fmt.Sprintf("upc: malformed embedded GCP table: %s", err))
+		}
+		defaultGcp = t
+	})
+	return defaultGcp
+}
+
+// splitGcp splits a GTIN-13, given as 13 digits, into its company
+// prefix and item reference, without the check digit.  A nil table
+// means DefaultGcpTable.
+func splitGcp(gtin13 string, t *GcpTable) (string, string, bool) {
+	if t == nil {
+		t = DefaultGcpTable()
+	}
+	n, ok := t.Length(gtin13)
+	if !ok || n == 0 {
+		return "", "", false
+	}
+	return gtin13[:n], gtin13[n:12], true
+}
+
+// CompanyPrefix returns the GS1 company prefix of the EAN according to
+// t, or DefaultGcpTable if t is nil.  The second return value is false
+// if the table has no company prefix for the EAN.
+func (e Ean) CompanyPrefix(t *GcpTable) (string, bool) {
+	prefix, _, ok := splitGcp(e.String(), t)
+	return prefix, ok
+}
+
+// ItemReference returns the digits of the EAN between its company
+// prefix and its check digit.  See CompanyPrefix.
+func (e Ean) ItemReference(t *GcpTable) (string, bool) {
+	_, item, ok := splitGcp(e.String(), t)
+	return item, ok
+}
+
+// CompanyPrefix returns the U.P.C. company prefix of the UPC according
+// to t, or DefaultGcpTable if t is nil.  That's the GS1 company prefix
+// without its leading zero, from 5 to 11 digits long.  The second
+// return value is false if the table has no company prefix for the
+// UPC.
+func (u Upc) CompanyPrefix(t *GcpTable) (string, bool) {
+	prefix, _, ok := splitGcp("0"+u.String(), t)
+	if !ok {
+		return "", false
+	}
+	return prefix[1:], true
+}
+
+// ItemReference returns the digits of the UPC between its company
+// prefix and its check digit.  See CompanyPrefix.
+func (u Upc) ItemReference(t *GcpTable) (string, bool) {
+	_, item, ok := splitGcp("0"+u.String(), t)
+	return item, ok
+}
+
+// CompanyPrefix returns the GS1 company prefix of the GTIN according
+// to t, or DefaultGcpTable if t is nil.  The GTIN is looked up as a
+// GTIN-13, without its indicator digit.
+func (g Gtin) CompanyPrefix(t *GcpTable) (string, bool) {
+	prefix, _, ok := splitGcp(g.Gtin14()[1:], t)
+	return prefix, ok
+}
+
+// ItemReference returns the digits of the GTIN between its company
+// prefix and its check digit.  See CompanyPrefix.
+func (g Gtin) ItemReference(t *GcpTable) (string, bool) {
+	_, item, ok := splitGcp(g.Gtin14()[1:], t)
+	return item, ok
+}
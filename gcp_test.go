@@ -0,0 +1,128 @@
+package upc
+
+import (
+	"strings"
+	"testing"
+)
+
+const gcpXml = `<?xml version="1.0" encoding="UTF-8"?>
+<GCPPrefixFormatList date="2024-05-01T00:00:00">
+  <entry prefix="004" gcpLength="7"/>
+  <entry prefix="0045496" gcpLength="7"/>
+  <entry prefix="0614141" gcpLength="7"/>
+  <entry prefix="08412345" gcpLength="11"/>
+  <entry prefix="4006381" gcpLength="7"/>
+  <entry prefix="40063813" gcpLength="9"/>
+  <entry prefix="2" gcpLength="0"/>
+</GCPPrefixFormatList>`
+
+const gcpJson = `{"GCPPrefixFormatList": {"entry": [
+  {"prefix": "004", "gcpLength": 7},
+  {"prefix": "0045496", "gcpLength": "7"},
+  {"prefix": "0614141", "gcpLength": 7},
+  {"prefix": "08412345", "gcpLength": 11},
+  {"prefix": "4006381", "gcpLength": 7},
+  {"prefix": "40063813", "gcpLength": 9},
+  {"prefix": "2", "gcpLength": 0}
+]}}`
+
+func TestGcpTable(t *testing.T) {
+	for _, data := range []string{gcpXml, "\n" + gcpJson} {
+		table, err := LoadGcpTable(strings.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		u, _ := Parse("045496830434") // Nintendo
+		if p, ok := u.CompanyPrefix(table); !ok || p != "045496" {
+			t.Errorf("%s: wrong company prefix: got %s", u, p)
+		}
+		if i, ok := u.ItemReference(table); !ok || i != "83043" {
+			t.Errorf("%s: wrong item reference: got %s", u, i)
+		}
+
+		u, _ = Parse("841234567897")
+		if p, ok := u.CompanyPrefix(table); !ok || p != "8412345678" {
+			t.Errorf("%s: wrong company prefix: got %s", u, p)
+		}
+		if i, ok := u.ItemReference(table); !ok || i != "9" {
+			t.Errorf("%s: wrong item reference: got %s", u, i)
+		}
+
+		// the longest prefix wins
+		e, _ := ParseEan("4006381333931")
+		if p, ok := e.CompanyPrefix(table); !ok || p != "400638133" {
+			t.Errorf("%s: wrong company prefix: got %s", e, p)
+		}
+		e, _ = ParseEan("4006381999991")
+		if i, ok := e.ItemReference(table); !ok || i != "99999" {
+			t.Errorf("%s: wrong item reference: got %s", e, i)
+		}
+
+		g := mustGtin("10614141000019")
+		if p, ok := g.CompanyPrefix(table); !ok || p != "0614141" {
+			t.Errorf("%s: wrong company prefix: got %s", g, p)
+		}
+
+		// no company prefix
+		e, _ = ParseEan("2012345000018")
+		if _, ok := e.CompanyPrefix(table); ok {
+			t.Errorf("%s: expected no company prefix", e)
+		}
+		e, _ = ParseEan("5012345678900")
+		if _, ok := e.CompanyPrefix(table); ok {
+			t.Errorf("%s: expected no company prefix", e)
+		}
+	}
+}
+
+func TestDefaultGcpTable(t *testing.T) {
+	u, _ := Parse("212345678909") // restricted circulation
+	if _, ok := u.CompanyPrefix(nil); ok {
+		t.Errorf("%s: expected no company prefix", u)
+	}
+	if n, ok := DefaultGcpTable().Length("9771234567003"); !ok || n != 0 {
+		t.Errorf("ISSN: expected a zero length got %d, %v", n, ok)
+	}
+
+	// member organizations' usual lengths
+	u, _ = Parse("036000291452")
+	if p, ok := u.CompanyPrefix(nil); !ok || p != "036000" {
+		t.Errorf("%s: wrong company prefix: got %q", u, p)
+	}
+	if i, ok := u.ItemReference(nil); !ok || i != "29145" {
+		t.Errorf("%s: wrong item reference: got %q", u, i)
+	}
+	e, _ := ParseEan("4006381333931")
+	if p, ok := e.CompanyPrefix(nil); !ok || p != "4006381" {
+		t.Errorf("%s: wrong company prefix: got %q", e, p)
+	}
+	e, _ = ParseEan("6921234567890")
+	if p, ok := e.CompanyPrefix(nil); !ok || p != "69212345" {
+		t.Errorf("%s: wrong company prefix: got %q", e, p)
+	}
+	// ISBNs and unallocated prefixes have no entry
+	for _, s := range []string{"9780306406157", "1401234567892"} {
+		if _, ok := DefaultGcpTable().Length(s); ok {
+			t.Errorf("%s: expected no entry", s)
+		}
+	}
+}
+
+func TestGcpTableWrong(t *testing.T) {
+	tables := []string{
+		`{"GCPPrefixFormatList": {"entry": []}}`,
+		`{"GCPPrefixFormatList": {"entry": [{"prefix": "06x", "gcpLength": 7}]}}`,
+		`{"GCPPrefixFormatList": {"entry": [{"prefix": "0614141", "gcpLength": 6}]}}`,
+		`{"GCPPrefixFormatList": {"entry": [{"prefix": "0", "gcpLength": 13}]}}`,
+		`<GCPPrefixFormatList><entry prefix="0" gcpLength="x"/></GCPPrefixFormatList>`,
+	}
+	for _, data := range tables {
+		if _, err := LoadGcpTable(strings.NewReader(data)); err != ErrGcpTable {
+			t.Errorf("%s: expected ErrGcpTable got %q", data, err)
+		}
+	}
+	if _, err := LoadGcpTable(strings.NewReader("{")); err == nil {
+		t.Errorf("expected an error got none")
+	}
+}
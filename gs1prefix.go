@@ -0,0 +1,205 @@
+package upc
+
+import "sort"
+
+// PrefixKind describes what a range of GS1 prefixes is used for.
+type PrefixKind int
+
+const (
+	// PrefixMember ranges are allocated to a GS1 member organization
+	// which assigns company prefixes in its country.
+	PrefixMember PrefixKind = iota
+
+	// PrefixRestricted ranges are for restricted circulation numbers,
+	// used within a company or a geographic region.  See Upc.IsLocal.
+	PrefixRestricted
+
+	// PrefixCoupon ranges are for coupons.
+	PrefixCoupon
+
+	// PrefixIssn is the range for serial publications (ISSN).
+	PrefixIssn
+
+	// PrefixBookland is the range for books (ISBN) and printed music
+	// (ISMN).
+	PrefixBookland
+
+	// PrefixRefund is the range for refund receipts.
+	PrefixRefund
+
+	// PrefixGlobalOffice ranges are managed by GS1 Global Office.
+	PrefixGlobalOffice
+)
+
+// PrefixRange is a range of 3-digit GS1 prefixes with the same
+// issuer.
+type PrefixRange struct {
+	First, Last  int
+	Kind         PrefixKind
+	Organization string
+	Country      string // empty unless Kind is PrefixMember
+}
+
+// Gs1Prefix returns the first 3 digits of the EAN, also known as the
+// GS1 prefix.
+func (e Ean) Gs1Prefix() int {
+	return int(e / 1000000000)
+}
+
+// PrefixRange returns the range containing the GS1 prefix of this
+// EAN.  It identifies the GS1 member organization which assigned the
+// company prefix and its country, or the special use of the EAN.  The
+// second return value is false if the prefix hasn't been allocated.
+//
+// A GS1 prefix shows where a company prefix was assigned, not where
+// the product was made.
+func (e Ean) PrefixRange() (PrefixRange, bool) {
+	p := e.Gs1Prefix()
+	i := sort.Search(len(prefixRanges), func(i int) bool {
+		return prefixRanges[i].Last >= p
+	})
+	if i < len(prefixRanges) && prefixRanges[i].First <= p {
+		return prefixRanges[i], true
+	}
+	return PrefixRange{}, false
+}
+
+// prefixRanges is the GS1 prefix list, sorted by prefix.
+var prefixRanges = []PrefixRange{
+	{0, 19, PrefixMember, "GS1 US", "United States"},
+	{20, 29, PrefixRestricted, "Restricted circulation within a geographic region", ""},
+	{30, 39, PrefixMember, "GS1 US", "United States"},
+	{40, 49, PrefixRestricted, "Restricted circulation within a company", ""},
+	{50, 59, PrefixCoupon, "GS1 US coupons", ""},
+	{60, 139, PrefixMember, "GS1 US", "United States"},
+	{200, 299, PrefixRestricted, "Restricted circulation within a geographic region", ""},
+	{300, 379, PrefixMember, "GS1 France", "France"},
+	{380, 380, PrefixMember, "GS1 Bulgaria", "Bulgaria"},
+	{383, 383, PrefixMember, "GS1 Slovenija", "Slovenia"},
+	{385, 385, PrefixMember, "GS1 Croatia", "Croatia"},
+	{387, 387, PrefixMember, "GS1 BIH", "Bosnia and Herzegovina"},
+	{389, 389, PrefixMember, "GS1 Montenegro", "Montenegro"},
+	{390, 390, PrefixMember, "GS1 Kosovo", "Kosovo"},
+	{400, 440, PrefixMember, "GS1 Germany", "Germany"},
+	{450, 459, PrefixMember, "GS1 Japan", "Japan"},
+	{460, 469, PrefixMember, "GS1 Russia", "Russia"},
+	{470, 470, PrefixMember, "GS1 Kyrgyzstan", "Kyrgyzstan"},
+	{471, 471, PrefixMember, "GS1 Taiwan", "Taiwan"},
+	{474, 474, PrefixMember, "GS1 Estonia", "Estonia"},
+	{475, 475, PrefixMember, "GS1 Latvia", "Latvia"},
+	{476, 476, PrefixMember, "GS1 Azerbaijan", "Azerbaijan"},
+	{477, 477, PrefixMember, "GS1 Lithuania", "Lithuania"},
+	{478, 478, PrefixMember, "GS1 Uzbekistan", "Uzbekistan"},
+	{479, 479, PrefixMember, "GS1 Sri Lanka", "Sri Lanka"},
+	{480, 480, PrefixMember, "GS1 Philippines", "Philippines"},
+	{481, 481, PrefixMember, "GS1 Belarus", "Belarus"},
+	{482, 482, PrefixMember, "GS1 Ukraine", "Ukraine"},
+	{483, 483, PrefixMember, "GS1 Turkmenistan", "Turkmenistan"},
+	{484, 484, PrefixMember, "GS1 Moldova", "Moldova"},
+	{485, 485, PrefixMember, "GS1 Armenia", "Armenia"},
+	{486, 486, PrefixMember, "GS1 Georgia", "Georgia"},
+	{487, 487, PrefixMember, "GS1 Kazakhstan", "Kazakhstan"},
+	{488, 488, PrefixMember, "GS1 Tajikistan", "Tajikistan"},
+	{489, 489, PrefixMember, "GS1 Hong Kong, China", "Hong Kong"},
+	{490, 499, PrefixMember, "GS1 Japan", "Japan"},
+	{500, 509, PrefixMember, "GS1 UK", "United Kingdom"},
+	{520, 521, PrefixMember, "GS1 Association Greece", "Greece"},
+	{528, 528, PrefixMember, "GS1 Lebanon", "Lebanon"},
+	{529, 529, PrefixMember, "GS1 Cyprus", "Cyprus"},
+	{530, 530, PrefixMember, "GS1 Albania", "Albania"},
+	{531, 531, PrefixMember, "GS1 North Macedonia", "North Macedonia"},
+	{535, 535, PrefixMember, "GS1 Malta", "Malta"},
+	{539, 539, PrefixMember, "GS1 Ireland", "Ireland"},
+	{540, 549, PrefixMember, "GS1 Belgium & Luxembourg", "Belgium and Luxembourg"},
+	{560, 560, PrefixMember, "GS1 Portugal", "Portugal"},
+	{569, 569, PrefixMember, "GS1 Iceland", "Iceland"},
+	{570, 579, PrefixMember, "GS1 Denmark", "Denmark"},
+	{590, 590, PrefixMember, "GS1 Poland", "Poland"},
+	{594, 594, PrefixMember, "GS1 Romania", "Romania"},
+	{599, 599, PrefixMember, "GS1 Hungary", "Hungary"},
+	{600, 601, PrefixMember, "GS1 South Africa", "South Africa"},
+	{603, 603, PrefixMember, "GS1 Ghana", "Ghana"},
+	{604, 604, PrefixMember, "GS1 Senegal", "Senegal"},
+	{605, 605, PrefixMember, "GS1 Uganda", "Uganda"},
+	{606, 606, PrefixMember, "GS1 Angola", "Angola"},
+	{607, 607, PrefixMember, "GS1 Oman", "Oman"},
+	{608, 608, PrefixMember, "GS1 Bahrain", "Bahrain"},
+	{609, 609, PrefixMember, "GS1 Mauritius", "Mauritius"},
+	{611, 611, PrefixMember, "GS1 Morocco", "Morocco"},
+	{613, 613, PrefixMember, "GS1 Algeria", "Algeria"},
+	{615, 615, PrefixMember, "GS1 Nigeria", "Nigeria"},
+	{616, 616, PrefixMember, "GS1 Kenya", "Kenya"},
+	{617, 617, PrefixMember, "GS1 Cameroon", "Cameroon"},
+	{618, 618, PrefixMember, "GS1 Côte d'Ivoire", "Côte d'Ivoire"},
+	{619, 619, PrefixMember, "GS1 Tunisia", "Tunisia"},
+	{620, 620, PrefixMember, "GS1 Tanzania", "Tanzania"},
+	{621, 621, PrefixMember, "GS1 Syria", "Syria"},
+	{622, 622, PrefixMember, "GS1 Egypt", "Egypt"},
+	{623, 623, PrefixMember, "GS1 Brunei", "Brunei"},
+	{624, 624, PrefixMember, "GS1 Libya", "Libya"},
+	{625, 625, PrefixMember, "GS1 Jordan", "Jordan"},
+	{626, 626, PrefixMember, "GS1 Iran", "Iran"},
+	{627, 627, PrefixMember, "GS1 Kuwait", "Kuwait"},
+	{628, 628, PrefixMember, "GS1 Saudi Arabia", "Saudi Arabia"},
+	{629, 629, PrefixMember, "GS1 Emirates", "United Arab Emirates"},
+	{630, 630, PrefixMember, "GS1 Qatar", "Qatar"},
+	{631, 631, PrefixMember, "GS1 Namibia", "Namibia"},
+	{640, 649, PrefixMember, "GS1 Finland", "Finland"},
+	{690, 699, PrefixMember, "GS1 China", "China"},
+	{700, 709, PrefixMember, "GS1 Norway", "Norway"},
+	{729, 729, PrefixMember, "GS1 Israel", "Israel"},
+	{730, 739, PrefixMember, "GS1 Sweden", "Sweden"},
+	{740, 740, PrefixMember, "GS1 Guatemala", "Guatemala"},
+	{741, 741, PrefixMember, "GS1 El Salvador", "El Salvador"},
+	{742, 742, PrefixMember, "GS1 Honduras", "Honduras"},
+	{743, 743, PrefixMember, "GS1 Nicaragua", "Nicaragua"},
+	{744, 744, PrefixMember, "GS1 Costa Rica", "Costa Rica"},
+	{745, 745, PrefixMember, "GS1 Panama", "Panama"},
+	{746, 746, PrefixMember, "GS1 Dominican Republic", "Dominican Republic"},
+	{750, 750, PrefixMember, "GS1 Mexico", "Mexico"},
+	{754, 755, PrefixMember, "GS1 Canada", "Canada"},
+	{759, 759, PrefixMember, "GS1 Venezuela", "Venezuela"},
+	{760, 769, PrefixMember, "GS1 Switzerland", "Switzerland"},
+	{770, 771, PrefixMember, "GS1 Colombia", "Colombia"},
+	{773, 773, PrefixMember, "GS1 Uruguay", "Uruguay"},
+	{775, 775, PrefixMember, "GS1 Peru", "Peru"},
+	{777, 777, PrefixMember, "GS1 Bolivia", "Bolivia"},
+	{778, 779, PrefixMember, "GS1 Argentina", "Argentina"},
+	{780, 780, PrefixMember, "GS1 Chile", "Chile"},
+	{784, 784, PrefixMember, "GS1 Paraguay", "Paraguay"},
+	{786, 786, PrefixMember, "GS1 Ecuador", "Ecuador"},
+	{789, 790, PrefixMember, "GS1 Brasil", "Brazil"},
+	{800, 839, PrefixMember, "GS1 Italy", "Italy"},
+	{840, 849, PrefixMember, "GS1 Spain", "Spain"},
+	{850, 850, PrefixMember, "GS1 Cuba", "Cuba"},
+	{858, 858, PrefixMember, "GS1 Slovakia", "Slovakia"},
+	{859, 859, PrefixMember, "GS1 Czech", "Czech Republic"},
+	{860, 860, PrefixMember, "GS1 Serbia", "Serbia"},
+	{865, 865, PrefixMember, "GS1 Mongolia", "Mongolia"},
+	{867, 867, PrefixMember, "GS1 North Korea", "North Korea"},
+	{868, 869, PrefixMember, "GS1 Turkey", "Turkey"},
+	{870, 879, PrefixMember, "GS1 Netherlands", "Netherlands"},
+	{880, 881, PrefixMember, "GS1 Korea", "South Korea"},
+	{883, 883, PrefixMember, "GS1 Myanmar", "Myanmar"},
+	{884, 884, PrefixMember, "GS1 Cambodia", "Cambodia"},
+	{885, 885, PrefixMember, "GS1 Thailand", "Thailand"},
+	{888, 888, PrefixMember, "GS1 Singapore", "Singapore"},
+	{890, 890, PrefixMember, "GS1 India", "India"},
+	{893, 893, PrefixMember, "GS1 Vietnam", "Vietnam"},
+	{894, 894, PrefixMember, "GS1 Bangladesh", "Bangladesh"},
+	{896, 896, PrefixMember, "GS1 Pakistan", "Pakistan"},
+	{899, 899, PrefixMember, "GS1 Indonesia", "Indonesia"},
+	{900, 919, PrefixMember, "GS1 Austria", "Austria"},
+	{930, 939, PrefixMember, "GS1 Australia", "Australia"},
+	{940, 949, PrefixMember, "GS1 New Zealand", "New Zealand"},
+	{950, 950, PrefixGlobalOffice, "GS1 Global Office", ""},
+	{951, 951, PrefixGlobalOffice, "GS1 Global Office (EPC Manager Number)", ""},
+	{955, 955, PrefixMember, "GS1 Malaysia", "Malaysia"},
+	{958, 958, PrefixMember, "GS1 Macau", "Macau"},
+	{960, 969, PrefixGlobalOffice, "GS1 Global Office (GTIN-8)", ""},
+	{977, 977, PrefixIssn, "Serial publications (ISSN)", ""},
+	{978, 979, PrefixBookland, "Bookland (ISBN and ISMN)", ""},
+	{980, 980, PrefixRefund, "Refund receipts", ""},
+	{981, 984, PrefixCoupon, "Common currency coupons", ""},
+	{990, 999, PrefixCoupon, "Coupons", ""},
+}
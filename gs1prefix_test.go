@@ -0,0 +1,71 @@
+package upc
+
+import "testing"
+
+// the expected prefix range of an EAN
+type prefixBreakdown struct {
+	prefix       int
+	kind         PrefixKind
+	organization string
+	country      string
+}
+
+var prefixTests = map[string]prefixBreakdown{
+	"0045496401771": {4, PrefixMember, "GS1 US", "United States"},
+	"0201234567899": {20, PrefixRestricted, "Restricted circulation within a geographic region", ""},
+	"0401234567893": {40, PrefixRestricted, "Restricted circulation within a company", ""},
+	"0012345678905": {1, PrefixMember, "GS1 US", "United States"},
+	"5055856406112": {505, PrefixMember, "GS1 UK", "United Kingdom"},
+	"4549673590600": {454, PrefixMember, "GS1 Japan", "Japan"},
+	"4006381333931": {400, PrefixMember, "GS1 Germany", "Germany"},
+	"7501031311309": {750, PrefixMember, "GS1 Mexico", "Mexico"},
+	"2001234567893": {200, PrefixRestricted, "Restricted circulation within a geographic region", ""},
+	"9771234567003": {977, PrefixIssn, "Serial publications (ISSN)", ""},
+	"9780306406157": {978, PrefixBookland, "Bookland (ISBN and ISMN)", ""},
+	"9790230671187": {979, PrefixBookland, "Bookland (ISBN and ISMN)", ""},
+	"9801234567892": {980, PrefixRefund, "Refund receipts", ""},
+	"9821234567890": {982, PrefixCoupon, "Common currency coupons", ""},
+	"9951234567894": {995, PrefixCoupon, "Coupons", ""},
+}
+
+func TestPrefixRange(t *testing.T) {
+	for s, expect := range prefixTests {
+		e, err := ParseEan(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		r, ok := e.PrefixRange()
+		if !ok {
+			t.Errorf("%s: no prefix range", s)
+			continue
+		}
+		got := prefixBreakdown{e.Gs1Prefix(), r.Kind, r.Organization, r.Country}
+		if got != expect {
+			t.Errorf("%s: wrong prefix\n got: %#v\nwant: %#v\n", s, got, expect)
+		}
+		if r.First > got.prefix || r.Last < got.prefix {
+			t.Errorf("%s: prefix %d outside range %d-%d", s, got.prefix, r.First, r.Last)
+		}
+	}
+}
+
+func TestPrefixRangeUnassigned(t *testing.T) {
+	for _, p := range []int{140, 199, 381, 510, 970, 985} {
+		e := Ean(int64(p) * 1000000000)
+		if r, ok := e.PrefixRange(); ok {
+			t.Errorf("%d: unexpected range %#v", p, r)
+		}
+	}
+}
+
+func TestPrefixRangesSorted(t *testing.T) {
+	for i, r := range prefixRanges {
+		if r.First > r.Last {
+			t.Errorf("%d-%d: empty range", r.First, r.Last)
+		}
+		if i > 0 && prefixRanges[i-1].Last >= r.First {
+			t.Errorf("%d-%d: overlaps or out of order", r.First, r.Last)
+		}
+	}
+}
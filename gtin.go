@@ -0,0 +1,136 @@
+package upc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Gtin represents a Global Trade Item Number of any length: GTIN-8,
+// GTIN-12 (UPC), GTIN-13 (EAN) or GTIN-14.  The number is stored in
+// its normalized 14-digit form without the check digit, together with
+// the length of the form it came from.
+//
+// Two Gtin values for the same item may differ only by their length,
+// so compare them with Equal, or use Key for map keys.
+type Gtin struct {
+	n      int64
+	length int
+}
+
+var ErrGtinLength = errors.New("GTIN must be 8, 12, 13 or 14 digits")
+var ErrGtinInvalidCheckDigit = errors.New("GTIN has an invalid check digit")
+
+// ParseGtin parses a string of 8, 12, 13 or 14 digits into a Gtin
+// value.  The following errors can be returned in addition to integer
+// parsing errors:
+//
+//	ErrGtinLength
+//	ErrGtinInvalidCheckDigit
+func ParseGtin(s string) (Gtin, error) {
+	switch len(s) {
+	case 8, 12, 13, 14:
+	default:
+		return Gtin{}, ErrGtinLength
+	}
+
+	var n int64
+	var check int
+	for i, b := range []byte(s) {
+		if b < 48 || b > 57 {
+			return Gtin{}, fmt.Errorf("Invalid GTIN digit: %c", b)
+		}
+		if i == len(s)-1 {
+			check = int(b - 48)
+		} else {
+			n *= 10
+			n += int64(b - 48)
+		}
+	}
+	g := Gtin{n: n, length: len(s)}
+	if g.CheckDigit() != check {
+		return Gtin{}, ErrGtinInvalidCheckDigit
+	}
+
+	return g, nil
+}
+
+// String returns the string representation of this GTIN in the length
+// it was parsed or converted from.
+func (g Gtin) String() string {
+	return fmt.Sprintf("%0*d%d", g.Len()-1, g.n, g.CheckDigit())
+}
+
+// Gtin14 returns the normalized, 14-digit string representation of
+// this GTIN.
+func (g Gtin) Gtin14() string {
+	return fmt.Sprintf("%013d%d", g.n, g.CheckDigit())
+}
+
+// Len returns the number of digits in the original form of this GTIN:
+// 8, 12, 13 or 14.
+func (g Gtin) Len() int {
+	if g.length == 0 {
+		return 14
+	}
+	return g.length
+}
+
+// CheckDigit returns the check digit that should be used as the last
+// digit of the GTIN.  It doesn't depend on the length.
+func (g Gtin) CheckDigit() int {
+	return Ean(g.n).CheckDigit()
+}
+
+// Key returns a value which is the same for every form of this GTIN,
+// suitable for use as a map key.
+func (g Gtin) Key() int64 {
+	return g.n
+}
+
+// Equal returns true if both GTINs identify the same item, regardless
+// of the length they came from.
+func (g Gtin) Equal(h Gtin) bool {
+	return g.n == h.n
+}
+
+// Upc returns the GTIN as a Upc.  The second return value is false if
+// the GTIN doesn't fit in 12 digits.
+func (g Gtin) Upc() (Upc, bool) {
+	if g.n >= 100000000000 {
+		return 0, false
+	}
+	return Upc(g.n), true
+}
+
+// Ean returns the GTIN as an Ean.  The second return value is false if
+// the GTIN doesn't fit in 13 digits.
+func (g Gtin) Ean() (Ean, bool) {
+	if g.n >= 1000000000000 {
+		return 0, false
+	}
+	return Ean(g.n), true
+}
+
+// Ean8 returns the GTIN as an Ean8.  The second return value is false
+// if the GTIN doesn't fit in 8 digits.
+func (g Gtin) Ean8() (Ean8, bool) {
+	if g.n >= 10000000 {
+		return 0, false
+	}
+	return Ean8(g.n), true
+}
+
+// Gtin returns this UPC as a GTIN-12.
+func (u Upc) Gtin() Gtin {
+	return Gtin{n: int64(u), length: 12}
+}
+
+// Gtin returns this EAN as a GTIN-13.
+func (e Ean) Gtin() Gtin {
+	return Gtin{n: int64(e), length: 13}
+}
+
+// Gtin returns this EAN-8 as a GTIN-8.
+func (e Ean8) Gtin() Gtin {
+	return Gtin{n: int64(e), length: 8}
+}
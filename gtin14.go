@@ -0,0 +1,61 @@
+package upc
+
+import "errors"
+
+var ErrInvalidIndicator = errors.New("GTIN-14 indicator must be a digit from 0 to 9")
+var ErrNotConsumerUnit = errors.New("GTIN already has a packaging indicator")
+
+// Indicator returns the packaging indicator, the first digit of the
+// 14-digit form of the GTIN.  Values 1 through 8 designate packaging
+// levels such as inner packs and cases, 9 designates a variable measure
+// item and 0 is used for the consumer unit itself.
+func (g Gtin) Indicator() int {
+	return int(g.n / 1000000000000)
+}
+
+// IsVariableMeasure returns true if the packaging indicator is 9.  The
+// quantity or weight of these trade items varies from one item to the
+// next.
+func (g Gtin) IsVariableMeasure() bool {
+	return g.Indicator() == 9
+}
+
+// ConsumerUnit returns the GTIN of the consumer unit contained in a
+// case-level GTIN-14, by dropping the indicator and recomputing the
+// check digit.  The result is a GTIN-12 when it fits in a UPC and a
+// GTIN-13 otherwise, so the Upc and Ean methods can be used on it
+// directly.
+func (g Gtin) ConsumerUnit() Gtin {
+	n := g.n % 1000000000000
+	if n < 100000000000 {
+		return Gtin{n: n, length: 12}
+	}
+	return Gtin{n: n, length: 13}
+}
+
+// CaseGtin returns the GTIN-14 for a packaging level of this consumer
+// unit.  The following errors can be returned:
+//
+//	ErrInvalidIndicator
+//	ErrNotConsumerUnit
+func (g Gtin) CaseGtin(indicator int) (Gtin, error) {
+	if indicator < 0 || indicator > 9 {
+		return Gtin{}, ErrInvalidIndicator
+	}
+	if g.Indicator() != 0 {
+		return Gtin{}, ErrNotConsumerUnit
+	}
+	return Gtin{n: int64(indicator)*1000000000000 + g.n, length: 14}, nil
+}
+
+// CaseGtin returns the GTIN-14 for a packaging level of the item
+// labeled with this UPC.  See Gtin.CaseGtin.
+func (u Upc) CaseGtin(indicator int) (Gtin, error) {
+	return u.Gtin().CaseGtin(indicator)
+}
+
+// CaseGtin returns the GTIN-14 for a packaging level of the item
+// labeled with this EAN.  See Gtin.CaseGtin.
+func (e Ean) CaseGtin(indicator int) (Gtin, error) {
+	return e.Gtin().CaseGtin(indicator)
+}
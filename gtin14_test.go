@@ -0,0 +1,88 @@
+package upc
+
+import "testing"
+
+// a breakdown of a GTIN-14 into its packaging attributes
+type gtin14Breakdown struct {
+	indicator       int
+	variableMeasure bool
+	consumerUnit    string
+	consumerUnitUpc bool
+	consumerUnitEan bool
+}
+
+var gtin14Tests = map[string]gtin14Breakdown{
+	"50045496830439": {
+		indicator:       5,
+		consumerUnit:    "045496830434",
+		consumerUnitUpc: true,
+		consumerUnitEan: true,
+	},
+	"14549673590607": {
+		indicator:       1,
+		consumerUnit:    "4549673590600",
+		consumerUnitEan: true,
+	},
+	"94549673590603": {
+		indicator:       9,
+		variableMeasure: true,
+		consumerUnit:    "4549673590600",
+		consumerUnitEan: true,
+	},
+	"00045496830434": {
+		consumerUnit:    "045496830434",
+		consumerUnitUpc: true,
+		consumerUnitEan: true,
+	},
+}
+
+func TestGtin14(t *testing.T) {
+	for s, expect := range gtin14Tests {
+		g, err := ParseGtin(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		var got gtin14Breakdown
+		got.indicator = g.Indicator()
+		got.variableMeasure = g.IsVariableMeasure()
+		c := g.ConsumerUnit()
+		got.consumerUnit = c.String()
+		_, got.consumerUnitUpc = c.Upc()
+		_, got.consumerUnitEan = c.Ean()
+		if got != expect {
+			t.Errorf("%s: wrong breakdown\n got: %#v\nwant: %#v\n", s, got, expect)
+		}
+
+		back, err := c.CaseGtin(got.indicator)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if back.String() != s {
+			t.Errorf("%s: wrong case GTIN: got %s", s, back)
+		}
+	}
+}
+
+func TestCaseGtin(t *testing.T) {
+	u, _ := Parse("045496830434")
+	g, err := u.CaseGtin(5)
+	if err != nil || g.String() != "50045496830439" {
+		t.Errorf("wrong case GTIN from UPC: got %s, %v", g, err)
+	}
+
+	e, _ := ParseEan("4549673590600")
+	g, err = e.CaseGtin(1)
+	if err != nil || g.String() != "14549673590607" {
+		t.Errorf("wrong case GTIN from EAN: got %s, %v", g, err)
+	}
+
+	if _, err := u.CaseGtin(10); err != ErrInvalidIndicator {
+		t.Errorf("expected ErrInvalidIndicator got %q", err)
+	}
+	if _, err := u.CaseGtin(-1); err != ErrInvalidIndicator {
+		t.Errorf("expected ErrInvalidIndicator got %q", err)
+	}
+	if _, err := g.CaseGtin(2); err != ErrNotConsumerUnit {
+		t.Errorf("expected ErrNotConsumerUnit got %q", err)
+	}
+}
@@ -0,0 +1,111 @@
+package upc
+
+import "testing"
+
+var gtinTests = map[string]string{
+	"96385074":       "00000096385074",
+	"045496830434":   "00045496830434",
+	"0045496830434":  "00045496830434",
+	"4549673590600":  "04549673590600",
+	"10045496830431": "10045496830431",
+}
+
+func TestGtin(t *testing.T) {
+	for s, gtin14 := range gtinTests {
+		g, err := ParseGtin(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if g.String() != s {
+			t.Errorf("%s: wrong string: got %s", s, g)
+		}
+		if g.Len() != len(s) {
+			t.Errorf("%s: wrong length: got %d", s, g.Len())
+		}
+		if g.Gtin14() != gtin14 {
+			t.Errorf("%s: wrong GTIN-14: got %s want %s", s, g.Gtin14(), gtin14)
+		}
+	}
+}
+
+func TestGtinEqual(t *testing.T) {
+	a, _ := ParseGtin("045496830434")
+	b, _ := ParseGtin("0045496830434")
+	c, _ := ParseGtin("00045496830434")
+	if !a.Equal(b) || !b.Equal(c) || a.Key() != c.Key() {
+		t.Errorf("expected %s, %s and %s to be equal", a, b, c)
+	}
+
+	seen := map[int64]bool{a.Key(): true}
+	if !seen[b.Key()] {
+		t.Errorf("expected %s to share a key with %s", b, a)
+	}
+
+	d, _ := ParseGtin("4549673590600")
+	if a.Equal(d) {
+		t.Errorf("expected %s and %s to differ", a, d)
+	}
+}
+
+func TestGtinConversion(t *testing.T) {
+	u, _ := Parse("045496830434")
+	g := u.Gtin()
+	if g.String() != "045496830434" || g.Len() != 12 {
+		t.Errorf("wrong GTIN from UPC: got %s", g)
+	}
+	if back, ok := g.Upc(); !ok || back != u {
+		t.Errorf("wrong UPC from GTIN: got %s", back)
+	}
+	if e, ok := g.Ean(); !ok || e.String() != "0045496830434" {
+		t.Errorf("wrong EAN from GTIN: got %s", e)
+	}
+
+	e, _ := ParseEan("4549673590600")
+	g = e.Gtin()
+	if g.String() != "4549673590600" || g.Len() != 13 {
+		t.Errorf("wrong GTIN from EAN: got %s", g)
+	}
+	if back, ok := g.Ean(); !ok || back != e {
+		t.Errorf("wrong EAN from GTIN: got %s", back)
+	}
+	if u, ok := g.Upc(); ok {
+		t.Errorf("unexpected UPC from %s: %s", g, u)
+	}
+
+	e8, _ := ParseEan8("96385074")
+	g = e8.Gtin()
+	if g.String() != "96385074" || g.Len() != 8 {
+		t.Errorf("wrong GTIN from EAN-8: got %s", g)
+	}
+	if back, ok := g.Ean8(); !ok || back != e8 {
+		t.Errorf("wrong EAN-8 from GTIN: got %s", back)
+	}
+	if u, ok := g.Upc(); !ok || u.String() != "000096385074" {
+		t.Errorf("wrong UPC from GTIN-8: got %s", u)
+	}
+
+	g, _ = ParseGtin("10045496830431")
+	if _, ok := g.Ean(); ok {
+		t.Errorf("unexpected EAN from %s", g)
+	}
+}
+
+func TestGtinWrong(t *testing.T) {
+	errs := map[string]error{
+		"":                ErrGtinLength,
+		"9638507":         ErrGtinLength,
+		"04549683043":     ErrGtinLength,
+		"100454968304310": ErrGtinLength,
+		"045496830435":    ErrGtinInvalidCheckDigit,
+		"10045496830432":  ErrGtinInvalidCheckDigit,
+	}
+	for s, want := range errs {
+		if _, err := ParseGtin(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+	if _, err := ParseGtin("04549683043x"); err == nil {
+		t.Errorf("expected an error got none")
+	}
+}
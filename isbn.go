@@ -0,0 +1,197 @@
+package upc
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var ErrIsbnLength = errors.New("ISBN must be 10 or 13 digits")
+var ErrIsbnInvalidCheckDigit = errors.New("ISBN has an invalid check digit")
+var ErrNotIsbn = errors.New("EAN is not an ISBN (must begin with 978 or 979)")
+var ErrNoIsbn10 = errors.New("ISBN beginning with 979 has no ISBN-10 form")
+var ErrIsbnUnknownRange = errors.New("ISBN is outside the known registration ranges")
+
+// ParseIsbn parses an ISBN-10 or ISBN-13 into its Bookland Ean.
+// Hyphens and spaces are ignored, and an ISBN-10 may end with an X
+// check character.  The following errors can be returned in addition
+// to integer parsing errors:
+//
+//	ErrIsbnLength
+//	ErrIsbnInvalidCheckDigit
+//	ErrNotIsbn
+func ParseIsbn(s string) (Ean, error) {
+	s = strings.NewReplacer("-", "", " ", "").Replace(s)
+	switch len(s) {
+	case 10:
+		for _, b := range []byte(s[:9]) {
+			if b < 48 || b > 57 {
+				return 0, fmt.Errorf("Invalid ISBN digit: %c", b)
+			}
+		}
+		check := s[9]
+		if check == 'x' {
+			check = 'X'
+		}
+		if check != 'X' && (check < 48 || check > 57) {
+			return 0, fmt.Errorf("Invalid ISBN check character: %c", check)
+		}
+		if isbn10CheckDigit(s[:9]) != check {
+			return 0, ErrIsbnInvalidCheckDigit
+		}
+
+		var n int64 = 978
+		for _, b := range []byte(s[:9]) {
+			n *= 10
+			n += int64(b - 48)
+		}
+		return Ean(n), nil
+	case 13:
+		e, err := ParseEan(s)
+		if err == ErrEanInvalidCheckDigit {
+			return 0, ErrIsbnInvalidCheckDigit
+		} else if err != nil {
+			return 0, err
+		}
+		if !e.IsIsbn() {
+			return 0, ErrNotIsbn
+		}
+		return e, nil
+	default:
+		return 0, ErrIsbnLength
+	}
+}
+
+// IsBookland returns true if the EAN begins with 978 or 979.  These
+// EANs are used for books and printed music.  See IsIsbn and IsIsmn.
+func (e Ean) IsBookland() bool {
+	p := e.Gs1Prefix()
+	return p == 978 || p == 979
+}
+
+// IsIsbn returns true if the EAN is an ISBN-13.  That is every
+// Bookland EAN except those beginning with 9790, which are ISMNs.
+func (e Ean) IsIsbn() bool {
+	return e.IsBookland() && e/100000000 != 9790
+}
+
+// Isbn10 returns the 10-character ISBN for this EAN, without hyphens.
+// Only ISBNs beginning with 978 have an ISBN-10 form.  The following
+// errors can be returned:
+//
+//	ErrNotIsbn
+//	ErrNoIsbn10
+func (e Ean) Isbn10() (string, error) {
+	if !e.IsIsbn() {
+		return "", ErrNotIsbn
+	}
+	if e.Gs1Prefix() != 978 {
+		return "", ErrNoIsbn10
+	}
+	digits := fmt.Sprintf("%09d", int64(e)%1000000000)
+	return digits + string(isbn10CheckDigit(digits)), nil
+}
+
+// isbn10CheckDigit returns the check character for the first 9
+// digits of an ISBN-10: a digit or X for 10.
+func isbn10CheckDigit(digits string) byte {
+	var sum int
+	for i, b := range []byte(digits) {
+		sum += (10 - i) * int(b-48)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte(48 + check)
+}
+
+// IsbnParts are the elements of an ISBN-13.
+type IsbnParts struct {
+	Prefix      string // 978 or 979
+	Group       string // registration group (language or country)
+	Registrant  string // publisher
+	Publication string // title or edition
+	CheckDigit  string
+}
+
+// String returns the hyphenated ISBN-13, e.g. 978-0-306-40615-7.
+func (p IsbnParts) String() string {
+	return strings.Join([]string{p.Prefix, p.Group, p.Registrant, p.Publication, p.CheckDigit}, "-")
+}
+
+// IsbnParts splits an ISBN-13 into its elements using the ISBN range
+// tables, which are embedded in the package.  The following errors
+// can be returned:
+//
+//	ErrNotIsbn
+//	ErrIsbnUnknownRange
+func (e Ean) IsbnParts() (IsbnParts, error) {
+	if !e.IsIsbn() {
+		return IsbnParts{}, ErrNotIsbn
+	}
+	s := e.String()
+	prefix, rest := s[:3], s[3:12]
+
+	isbnRangesOnce.Do(loadIsbnRanges)
+	for n := 1; n <= 5 && n < len(rest); n++ {
+		ranges, ok := isbnRanges[prefix+"-"+rest[:n]]
+		if !ok {
+			continue
+		}
+		group, rest := rest[:n], rest[n:]
+		window := (rest + "0000000")[:7]
+		for _, r := range ranges {
+			if r.first <= window && window <= r.last && r.length < len(rest) {
+				return IsbnParts{
+					Prefix:      prefix,
+					Group:       group,
+					Registrant:  rest[:r.length],
+					Publication: rest[r.length:],
+					CheckDigit:  s[12:],
+				}, nil
+			}
+		}
+		return IsbnParts{}, ErrIsbnUnknownRange
+	}
+	return IsbnParts{}, ErrIsbnUnknownRange
+}
+
+// isbnRange is a range of registrants with the same number of digits.
+// The bounds are the first 7 digits after the registration group.
+type isbnRange struct {
+	first, last string
+	length      int
+}
+
+//go:generate go run isbnranges_gen.go RangeMessage.xml
+//go:embed isbnranges.txt
+var isbnRangeData string
+
+var isbnRangesOnce sync.Once
+var isbnRanges map[string][]isbnRange
+
+// loadIsbnRanges parses the embedded ISBN range table.  The table is
+// part of the package, so a malformed line is a programming error.
+func loadIsbnRanges() {
+	isbnRanges = make(map[string][]isbnRange)
+	for _, line := range strings.Split(isbnRangeData, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for _, f := range fields[1:] {
+			bounds := strings.Split(f, "-")
+			if len(bounds) != 2 || len(bounds[0]) != len(bounds[1]) || len(bounds[0]) > 7 {
+				panic("upc: malformed ISBN range " + f)
+			}
+			isbnRanges[fields[0]] = append(isbnRanges[fields[0]], isbnRange{
+				first:  (bounds[0] + "0000000")[:7],
+				last:   (bounds[1] + "9999999")[:7],
+				length: len(bounds[0]),
+			})
+		}
+	}
+}
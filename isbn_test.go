@@ -0,0 +1,121 @@
+package upc
+
+import "testing"
+
+// the expected forms of an ISBN
+type isbnBreakdown struct {
+	ean        string
+	isbn10     string
+	hyphenated string
+}
+
+var isbnTests = map[string]isbnBreakdown{
+	"0-306-40615-2": {
+		ean:        "9780306406157",
+		isbn10:     "0306406152",
+		hyphenated: "978-0-306-40615-7",
+	},
+	"978-1-4028-9462-6": {
+		ean:        "9781402894626",
+		isbn10:     "1402894627",
+		hyphenated: "978-1-4028-9462-6",
+	},
+	"3-16-148410-x": {
+		ean:        "9783161484100",
+		isbn10:     "316148410X",
+		hyphenated: "978-3-16-148410-0",
+	},
+	"4 87311 336 9": {
+		ean:        "9784873113364",
+		isbn10:     "4873113369",
+		hyphenated: "978-4-87311-336-4",
+	},
+	"9791090636071": {
+		ean:        "9791090636071",
+		hyphenated: "979-10-90636-07-1",
+	},
+	"88-04-66823-7": {
+		ean:        "9788804668237",
+		isbn10:     "8804668237",
+		hyphenated: "978-88-04-66823-7",
+	},
+	"950-07-0395-5": {
+		ean:        "9789500703956",
+		isbn10:     "9500703955",
+		hyphenated: "978-950-07-0395-6",
+	},
+	"979-8-6412-3456-4": {
+		ean:        "9798641234564",
+		hyphenated: "979-8-6412-3456-4",
+	},
+}
+
+func TestIsbn(t *testing.T) {
+	for s, expect := range isbnTests {
+		e, err := ParseIsbn(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		var got isbnBreakdown
+		got.ean = e.String()
+		got.isbn10, _ = e.Isbn10()
+		if p, err := e.IsbnParts(); err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else {
+			got.hyphenated = p.String()
+		}
+		if got != expect {
+			t.Errorf("%s: wrong breakdown\n got: %#v\nwant: %#v\n", s, got, expect)
+		}
+		if !e.IsBookland() || !e.IsIsbn() {
+			t.Errorf("%s: expected a Bookland ISBN", s)
+		}
+	}
+}
+
+func TestIsbnWrong(t *testing.T) {
+	errs := map[string]error{
+		"":                  ErrIsbnLength,
+		"030640615":         ErrIsbnLength,
+		"0-306-40615-3":     ErrIsbnInvalidCheckDigit,
+		"9780306406158":     ErrIsbnInvalidCheckDigit,
+		"0045496830434":     ErrNotIsbn,
+		"979-0-2306-7118-7": ErrNotIsbn,
+	}
+	for s, want := range errs {
+		if _, err := ParseIsbn(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+
+	digit := []string{
+		"X306406152",
+		"030640615Y",
+		"97803064061x7",
+	}
+	for _, s := range digit {
+		if _, err := ParseIsbn(s); err == nil {
+			t.Errorf("%s: expected an error got none", s)
+		}
+	}
+
+	e, _ := ParseEan("9791090636071")
+	if _, err := e.Isbn10(); err != ErrNoIsbn10 {
+		t.Errorf("%s: expected ErrNoIsbn10 got %q", e, err)
+	}
+	e, _ = ParseEan("0045496830434")
+	if e.IsBookland() {
+		t.Errorf("%s: unexpected Bookland", e)
+	}
+	if _, err := e.IsbnParts(); err != ErrNotIsbn {
+		t.Errorf("%s: expected ErrNotIsbn got %q", e, err)
+	}
+	// an unallocated group, and an unallocated range in a group
+	for _, s := range []string{"9789999999991", "9798000000014"} {
+		e, _ = ParseEan(s)
+		if _, err := e.IsbnParts(); err != ErrIsbnUnknownRange {
+			t.Errorf("%s: expected ErrIsbnUnknownRange got %q", e, err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+//go:build ignore
+
+// This program writes isbnranges.txt from the International ISBN
+// Agency's range message.  Download RangeMessage.xml from
+// https://www.isbn-international.org/range_file_generation and run
+//
+//	go run isbnranges_gen.go RangeMessage.xml
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+type rangeMessage struct {
+	Groups []group `xml:"RegistrationGroups>Group"`
+}
+
+type group struct {
+	Prefix string `xml:"Prefix"`
+	Rules  []struct {
+		Range  string `xml:"Range"`
+		Length int    `xml:"Length"`
+	} `xml:"Rules>Rule"`
+}
+
+const header = `# ISBN registration group ranges, from the International ISBN Agency's
+# range message (https://www.isbn-international.org/range_file_generation).
+#
+# Each line holds an EAN prefix and registration group, followed by the
+# registrant ranges for that group.  A range applies to the first 7
+# digits after the group; the number of digits in its bounds is the
+# length of the registrant element.  Groups and ranges which aren't
+# listed haven't been allocated yet.
+#
+# Regenerate the table from the agency's RangeMessage.xml with
+# "go generate".
+`
+
+func main() {
+	name := "RangeMessage.xml"
+	if len(os.Args) > 1 {
+		name = os.Args[1]
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	var msg rangeMessage
+	if err := xml.NewDecoder(f).Decode(&msg); err != nil {
+		log.Fatal(err)
+	}
+
+	out, err := os.Create("isbnranges.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	w := bufio.NewWriter(out)
+	w.WriteString(header)
+	for _, g := range msg.Groups {
+		var ranges []string
+		for _, r := range g.Rules {
+			first, last, ok := strings.Cut(r.Range, "-")
+			if !ok || len(first) != 7 || len(last) != 7 || r.Length > 7 {
+				log.Fatalf("%s: malformed range %q", g.Prefix, r.Range)
+			}
+			// a length of zero marks a range which isn't allocated
+			if r.Length > 0 {
+				ranges = append(ranges, first[:r.Length]+"-"+last[:r.Length])
+			}
+		}
+		if len(ranges) > 0 {
+			fmt.Fprintf(w, "%s\t%s\n", g.Prefix, strings.Join(ranges, " "))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
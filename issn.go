@@ -0,0 +1,160 @@
+package upc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrIssnLength = errors.New("ISSN must be 8 characters")
+var ErrIssnInvalidCheckDigit = errors.New("ISSN has an invalid check digit")
+var ErrNotIssn = errors.New("EAN is not an ISSN (must begin with 977)")
+var ErrIssnVariant = errors.New("ISSN variant must be from 00 to 99")
+var ErrIssueLength = errors.New("issue number add-on must be 2 digits")
+var ErrIsmnLength = errors.New("ISMN must be 10 or 13 characters")
+var ErrIsmnInvalidCheckDigit = errors.New("ISMN has an invalid check digit")
+var ErrNotIsmn = errors.New("EAN is not an ISMN (must begin with 9790)")
+
+// IsIssn returns true if the EAN begins with 977.  These EANs are used
+// for serial publications such as magazines.
+func (e Ean) IsIssn() bool {
+	return e.Gs1Prefix() == 977
+}
+
+// Issn returns the International Standard Serial Number for this EAN
+// in its standard, hyphenated form, e.g. 0317-8471.  The check
+// character isn't part of the EAN, so it's computed.
+func (e Ean) Issn() (string, error) {
+	if !e.IsIssn() {
+		return "", ErrNotIssn
+	}
+	digits := fmt.Sprintf("%07d", (int64(e)/100)%10000000)
+	return digits[:4] + "-" + digits[4:] + string(issnCheckDigit(digits)), nil
+}
+
+// IssnVariant returns the 2-digit price/variant code of an ISSN EAN.
+// Publishers use it to distinguish prices or editions of the same
+// serial.
+func (e Ean) IssnVariant() int {
+	return int(e % 100)
+}
+
+// IssnEan builds the EAN for an ISSN, with or without its hyphen, and
+// a price/variant code.  The following errors can be returned in
+// addition to integer parsing errors:
+//
+//	ErrIssnLength
+//	ErrIssnInvalidCheckDigit
+//	ErrIssnVariant
+func IssnEan(issn string, variant int) (Ean, error) {
+	issn = strings.Replace(issn, "-", "", 1)
+	if len(issn) != 8 {
+		return 0, ErrIssnLength
+	}
+	if variant < 0 || variant > 99 {
+		return 0, ErrIssnVariant
+	}
+
+	var n int64 = 977
+	for _, b := range []byte(issn[:7]) {
+		if b < 48 || b > 57 {
+			return 0, fmt.Errorf("Invalid ISSN digit: %c", b)
+		}
+		n *= 10
+		n += int64(b - 48)
+	}
+	check := issn[7]
+	if check == 'x' {
+		check = 'X'
+	}
+	if issnCheckDigit(issn[:7]) != check {
+		return 0, ErrIssnInvalidCheckDigit
+	}
+
+	return Ean(n*100 + int64(variant)), nil
+}
+
+// issnCheckDigit returns the check character for the first 7 digits
+// of an ISSN: a digit or X for 10.
+func issnCheckDigit(digits string) byte {
+	var sum int
+	for i, b := range []byte(digits) {
+		sum += (8 - i) * int(b-48)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte(48 + check)
+}
+
+// ParsePeriodical parses an ISSN EAN followed by the 2-digit issue
+// number add-on printed next to the barcode of a magazine.  The
+// add-on may be separated from the EAN by a space.  The following
+// errors can be returned in addition to those of ParseEanWithAddOn:
+//
+//	ErrIssueLength
+//	ErrNotIssn
+func ParsePeriodical(s string) (Ean, int, error) {
+	p, err := ParseEanWithAddOn(s)
+	if err == ErrAddOnLength {
+		return 0, 0, ErrIssueLength
+	} else if err != nil {
+		return 0, 0, err
+	}
+	issue, ok := p.AddOn.Issue()
+	if !ok {
+		return 0, 0, ErrIssueLength
+	}
+	if !p.Ean.IsIssn() {
+		return 0, 0, ErrNotIssn
+	}
+	return p.Ean, issue, nil
+}
+
+// IsIsmn returns true if the EAN begins with 9790.  These EANs are
+// used for printed music.
+func (e Ean) IsIsmn() bool {
+	return e/100000000 == 9790
+}
+
+// Ismn returns the International Standard Music Number for this EAN
+// in its 10-character form, e.g. M230671187.  Since 2008 the 13-digit
+// EAN is itself the ISMN, so this is only needed for older
+// references.
+func (e Ean) Ismn() (string, error) {
+	if !e.IsIsmn() {
+		return "", ErrNotIsmn
+	}
+	return fmt.Sprintf("M%08d%d", int64(e)%100000000, e.CheckDigit()), nil
+}
+
+// ParseIsmn parses a 10-character ISMN beginning with M or a 13-digit
+// ISMN beginning with 9790 into its Bookland Ean.  Hyphens and spaces
+// are ignored.  The 10-character form has the same check digit as the
+// EAN.  The following errors can be returned in addition to integer
+// parsing errors:
+//
+//	ErrIsmnLength
+//	ErrIsmnInvalidCheckDigit
+//	ErrNotIsmn
+func ParseIsmn(s string) (Ean, error) {
+	s = strings.NewReplacer("-", "", " ", "").Replace(s)
+	if len(s) == 10 && (s[0] == 'M' || s[0] == 'm') {
+		s = "9790" + s[1:]
+	}
+	if len(s) != 13 {
+		return 0, ErrIsmnLength
+	}
+
+	e, err := ParseEan(s)
+	if err == ErrEanInvalidCheckDigit {
+		return 0, ErrIsmnInvalidCheckDigit
+	} else if err != nil {
+		return 0, err
+	}
+	if !e.IsIsmn() {
+		return 0, ErrNotIsmn
+	}
+	return e, nil
+}
@@ -0,0 +1,120 @@
+package upc
+
+import "testing"
+
+var issnTests = map[string]struct {
+	issn    string
+	variant int
+}{
+	"9770317847001": {"0317-8471", 0},
+	"9770317847018": {"0317-8471", 1},
+	"9772434561051": {"2434-561X", 5},
+}
+
+func TestIssn(t *testing.T) {
+	for s, expect := range issnTests {
+		e, err := ParseEan(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if !e.IsIssn() {
+			t.Errorf("%s: expected an ISSN", s)
+		}
+		if issn, err := e.Issn(); err != nil || issn != expect.issn {
+			t.Errorf("%s: wrong ISSN: got %s, %v", s, issn, err)
+		}
+		if e.IssnVariant() != expect.variant {
+			t.Errorf("%s: wrong variant: got %d", s, e.IssnVariant())
+		}
+
+		back, err := IssnEan(expect.issn, expect.variant)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if back != e {
+			t.Errorf("%s: wrong EAN from ISSN: got %s", s, back)
+		}
+	}
+}
+
+func TestIssnWrong(t *testing.T) {
+	if _, err := IssnEan("0317-847", 0); err != ErrIssnLength {
+		t.Errorf("expected ErrIssnLength got %q", err)
+	}
+	if _, err := IssnEan("0317-8472", 0); err != ErrIssnInvalidCheckDigit {
+		t.Errorf("expected ErrIssnInvalidCheckDigit got %q", err)
+	}
+	if _, err := IssnEan("0317-8471", 100); err != ErrIssnVariant {
+		t.Errorf("expected ErrIssnVariant got %q", err)
+	}
+	if _, err := IssnEan("0317-x471", 0); err == nil {
+		t.Errorf("expected an error got none")
+	}
+
+	e, _ := ParseEan("0045496830434")
+	if _, err := e.Issn(); err != ErrNotIssn {
+		t.Errorf("%s: expected ErrNotIssn got %q", e, err)
+	}
+}
+
+func TestPeriodical(t *testing.T) {
+	for _, s := range []string{"9770317847001 05", "977031784700105"} {
+		e, issue, err := ParsePeriodical(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if e.String() != "9770317847001" || issue != 5 {
+			t.Errorf("%s: got %s issue %d", s, e, issue)
+		}
+	}
+
+	errs := map[string]error{
+		"9770317847001":     ErrIssueLength,
+		"9770317847001 5":   ErrIssueLength,
+		"9770317847001 123": ErrIssueLength,
+		"0045496401771 05":  ErrNotIssn,
+		"9770317847002 05":  ErrEanInvalidCheckDigit,
+	}
+	for s, want := range errs {
+		if _, _, err := ParsePeriodical(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+	if _, _, err := ParsePeriodical("9770317847001 0x"); err == nil {
+		t.Errorf("expected an error got none")
+	}
+}
+
+func TestIsmn(t *testing.T) {
+	for _, s := range []string{"M-2306-7118-7", "m230671187", "979-0-2306-7118-7"} {
+		e, err := ParseIsmn(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if e.String() != "9790230671187" {
+			t.Errorf("%s: wrong EAN: got %s", s, e)
+		}
+		if !e.IsIsmn() || !e.IsBookland() || e.IsIsbn() {
+			t.Errorf("%s: expected an ISMN", s)
+		}
+		if ismn, err := e.Ismn(); err != nil || ismn != "M230671187" {
+			t.Errorf("%s: wrong ISMN: got %s, %v", s, ismn, err)
+		}
+	}
+
+	errs := map[string]error{
+		"M-2306-7118":       ErrIsmnLength,
+		"M-2306-7118-8":     ErrIsmnInvalidCheckDigit,
+		"978-0-306-40615-7": ErrNotIsmn,
+	}
+	for s, want := range errs {
+		if _, err := ParseIsmn(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+
+	e, _ := ParseEan("9780306406157")
+	if _, err := e.Ismn(); err != ErrNotIsmn {
+		t.Errorf("%s: expected ErrNotIsmn got %q", e, err)
+	}
+}
@@ -0,0 +1,159 @@
+package upc
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// itfDigits is the pattern of each digit in Interleaved 2 of 5, as 1
+// for a wide element and 0 for a narrow one.  Digits are encoded in
+// pairs: the first in the five bars and the second in the five spaces
+// between them.
+var itfDigits = [10]string{
+	"00110", "10001", "01001", "11000", "00101",
+	"10100", "01100", "00011", "10010", "01010",
+}
+
+// ITF-14 start and stop patterns.  Wide bars and spaces are itfWide
+// modules, which is within the 2.25 to 3 times a narrow one that GS1
+// allows, and the quiet zones are itfQuiet modules.
+const (
+	itfStart = "1010"
+	itfStop  = "11101"
+	itfWide  = 3
+	itfQuiet = 10
+)
+
+// Wide to narrow ratios accepted when reading an ITF-14, more lenient
+// than those allowed for printing.
+const (
+	minItfRatio = 1.8
+	maxItfRatio = 3.6
+)
+
+// Itf14 returns the ITF-14 barcode of the GTIN, which is printed on
+// cases and cartons where a UPC-A or EAN-13 wouldn't scan reliably.  A
+// GTIN shorter than 14 digits is padded with zeros.  Render it with
+// SVG or Image, which surround it with bearer bars.
+func (g Gtin) Itf14() Symbol {
+	digits := g.Gtin14()
+	s, w := newSymbol(SymbolItf14, digits, itfQuiet)
+	w.pattern(itfStart, false)
+	for i := 0; i < len(digits); i += 2 {
+		bars, spaces := itfDigits[digits[i]-48], itfDigits[digits[i+1]-48]
+		for k := 0; k < 5; k++ {
+			w.pattern(itfElement('1', bars[k]), false)
+			w.pattern(itfElement('0', spaces[k]), false)
+		}
+	}
+	w.pattern(itfStop, false)
+	s.QuietRight = itfQuiet
+	w.quiet(s.QuietRight)
+	return *s
+}
+
+// itfElement returns the modules of a bar or space, as 1 or 0, which
+// is wide if wide is 1.
+func itfElement(b byte, wide byte) string {
+	if wide == '1' {
+		return strings.Repeat(string(b), itfWide)
+	}
+	return string(b)
+}
+
+// readItf reads an ITF-14 whose start pattern begins with the bar at
+// run i.  It returns the barcode and the index of the run after its
+// last bar.  The width of the narrow and wide elements is found from
+// each pair of digits, so it may vary across the barcode, and the check
+// digit is validated like that of any GTIN.
+func readItf(runs []float64, i int) (Barcode, int, bool) {
+	end := i + len(itfStart) + 7*10 + 3
+	if end >= len(runs) {
+		return Barcode{}, 0, false
+	}
+	start := runs[i : i+4]
+	narrow := (start[0] + start[1] + start[2] + start[3]) / 4
+	if !fits(start, itfStart, narrow) || i > 1 && runs[i-1] < minQuiet*narrow {
+		return Barcode{}, 0, false
+	}
+
+	var digits []byte
+	var confidence float64
+	wide := 0.0
+	for j := i + 4; j < end-3; j += 10 {
+		pair := runs[j : j+10]
+		narrow, wide = itfWidths(pair)
+		if wide < minItfRatio*narrow || wide > maxItfRatio*narrow {
+			return Barcode{}, 0, false
+		}
+		for k := 0; k < 2; k++ {
+			var group [5]float64
+			for e := range group {
+				group[e] = pair[2*e+k]
+			}
+			d, c, ok := readItfDigit(group, narrow, wide)
+			if !ok {
+				return Barcode{}, 0, false
+			}
+			digits = append(digits, d)
+			confidence += c
+		}
+	}
+
+	stop := runs[end-3 : end]
+	if math.Abs(stop[0]-wide) > maxVariance*narrow ||
+		math.Abs(stop[1]/narrow-1) > maxVariance || math.Abs(stop[2]/narrow-1) > maxVariance {
+		return Barcode{}, 0, false
+	}
+	if runs[end] < minQuiet*narrow {
+		return Barcode{}, 0, false
+	}
+	b := Barcode{Format: SymbolItf14, Digits: string(digits), Confidence: confidence / 14}
+	if _, err := ParseGtin(b.Digits); err != nil {
+		return Barcode{}, 0, false
+	}
+	return b, end, true
+}
+
+// itfWidths returns the mean widths of the six narrow and four wide
+// elements of a pair of digits.
+func itfWidths(pair []float64) (narrow, wide float64) {
+	w := append([]float64(nil), pair...)
+	sort.Float64s(w)
+	for _, v := range w[:6] {
+		narrow += v
+	}
+	for _, v := range w[6:] {
+		wide += v
+	}
+	return narrow / 6, wide / 4
+}
+
+// readItfDigit reads the digit encoded in five bars or spaces, given
+// the width of narrow and wide elements.  It returns the digit and the
+// confidence in it, which is how much nearer the widths are to it than
+// to the next nearest digit.
+func readItfDigit(group [5]float64, narrow, wide float64) (byte, float64, bool) {
+	var digit byte
+	best, next := maxDigitVariance, maxDigitVariance
+	for d, p := range itfDigits {
+		var sum float64
+		for k := range group {
+			want := narrow
+			if p[k] == '1' {
+				want = wide
+			}
+			sum += math.Abs(group[k]-want) / narrow
+		}
+		if sum < best {
+			best, next, digit = sum, best, byte('0'+d)
+		} else if sum < next {
+			next = sum
+		}
+	}
+	if digit == 0 {
+		return 0, 0, false
+	}
+	return digit, 1 - best/next, true
+}
@@ -0,0 +1,99 @@
+package upc
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestItf14Symbol(t *testing.T) {
+	g := mustGtin("10614141000019")
+	s := g.Itf14()
+	// 10: bars WNNNW interleaved with spaces NNWWN
+	want := strings.Repeat("0", 10) + "1010" + "111" + "0" + "1" + "0" + "1" + "000" + "1" + "000" + "111" + "0"
+	if got := s.String(); !strings.HasPrefix(got, want) || !strings.HasSuffix(got, "11101"+strings.Repeat("0", 10)) {
+		t.Errorf("wrong modules: got %s", got)
+	}
+	if len(s.Modules) != 10+4+7*18+5+10 || s.Format != SymbolItf14 || s.Digits != "10614141000019" {
+		t.Errorf("wrong symbol: got %d modules %v %s", len(s.Modules), s.Format, s.Digits)
+	}
+
+	// shorter GTINs are padded
+	if s := mustGtin("036000291452").Itf14(); s.Digits != "00036000291452" {
+		t.Errorf("wrong digits: got %s", s.Digits)
+	}
+}
+
+func TestItf14Render(t *testing.T) {
+	svg := mustGtin("10614141000019").Itf14().SVG(nil)
+	// 155 modules of 1.016mm and the bearer bars either side
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="167.08mm" height="50.236mm"`) {
+		t.Errorf("wrong size: %s", svg[:strings.IndexByte(svg, '\n')])
+	}
+	// the background, 39 bars and 4 bearer bars
+	if n := strings.Count(svg, "<rect"); n != 1+39+4 {
+		t.Errorf("wrong number of rectangles: got %d", n)
+	}
+	if n := strings.Count(svg, "<text"); n != 14 {
+		t.Errorf("wrong number of digits: got %d", n)
+	}
+}
+
+func TestItf14Decode(t *testing.T) {
+	for _, s := range []string{"10614141000019", "00036000291452", "40012345678903"} {
+		sym := mustGtin(s).Itf14()
+
+		b, err := DecodeRuns(runsOf(sym))
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if b.Format != SymbolItf14 || b.Digits != s || b.Confidence != 1 {
+			t.Errorf("%s: got %v %s %f", s, b.Format, b.Digits, b.Confidence)
+		}
+
+		// wide elements 2.5 times narrow ones, read backwards
+		runs := runsOf(sym)
+		for i := range runs {
+			if runs[i] == itfWide {
+				runs[i] = 2.5
+			}
+		}
+		b, err = DecodeRuns(reverse(runs))
+		if err != nil {
+			t.Errorf("%s reversed: %s", s, err)
+		} else if b.Digits != s || b.Confidence <= 0 || b.Confidence > 1 {
+			t.Errorf("%s reversed: got %s %f", s, b.Digits, b.Confidence)
+		}
+
+		// photos, bearer bars and all
+		img := sym.Image(&RenderOptions{DPI: 100})
+		for name, img := range map[string]*image.Gray{
+			"plain":  img,
+			"skewed": addNoise(blur(rotate(img, 8), 1), 30),
+		} {
+			barcodes, err := DecodeImage(img)
+			if err != nil {
+				t.Errorf("%s %s: %s", name, s, err)
+			} else if len(barcodes) != 1 || barcodes[0].Format != SymbolItf14 || barcodes[0].Digits != s {
+				t.Errorf("%s %s: got %v", name, s, barcodes)
+			}
+		}
+	}
+
+	// swapping the bars and spaces of the first pair reads 01, which
+	// breaks the check digit
+	runs := runsOf(mustGtin("10614141000019").Itf14())
+	for i := 5; i < 15; i += 2 {
+		runs[i], runs[i+1] = runs[i+1], runs[i]
+	}
+	if _, err := DecodeRuns(runs); err != ErrNoBarcode {
+		t.Errorf("expected ErrNoBarcode got %v", err)
+	}
+
+	// a scanline cut off just after the stop pattern lacks the quiet
+	// zone, so it may be part of a longer Interleaved 2 of 5
+	runs = runsOf(mustGtin("10614141000019").Itf14())
+	runs[len(runs)-1] = 1
+	if _, err := DecodeRuns(runs); err != ErrNoBarcode {
+		t.Errorf("truncated: expected ErrNoBarcode got %v", err)
+	}
+}
@@ -0,0 +1,306 @@
+package upc
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// NdcLayout is the segment layout of a 10-digit National Drug Code:
+// the lengths of its labeler, product and package codes.  The FDA
+// assigns the labeler code, and its length determines the layout.
+type NdcLayout int
+
+const (
+	Ndc442 NdcLayout = iota + 1
+	Ndc532
+	Ndc541
+)
+
+// String returns the layout in its usual notation, e.g. 4-4-2.
+func (l NdcLayout) String() string {
+	switch l {
+	case Ndc442:
+		return "4-4-2"
+	case Ndc532:
+		return "5-3-2"
+	case Ndc541:
+		return "5-4-1"
+	default:
+		return "unknown"
+	}
+}
+
+// segments returns the lengths of the labeler, product and package
+// codes.
+func (l NdcLayout) segments() (int, int, int) {
+	switch l {
+	case Ndc442:
+		return 4, 4, 2
+	case Ndc532:
+		return 5, 3, 2
+	case Ndc541:
+		return 5, 4, 1
+	default:
+		return 0, 0, 0
+	}
+}
+
+// NdcResolver determines the segment layout of an unhyphenated,
+// 10-digit NDC, usually from its labeler code.  The second return
+// value is false if the layout is unknown.
+type NdcResolver interface {
+	NdcLayout(digits string) (NdcLayout, bool)
+}
+
+// LabelerTable is an NdcResolver mapping labeler codes of 4 or 5
+// digits to the layout of the NDCs they assign.  It can be loaded from
+// the FDA's NDC directory with LoadLabelerTable.
+type LabelerTable map[string]NdcLayout
+
+var ErrLabelerTable = errors.New("NDC directory must have a PRODUCTNDC column of hyphenated product codes")
+
+// LoadLabelerTable reads the labeler codes of the FDA's NDC directory,
+// from its product.txt or package.txt file.  Both are tab-separated
+// with a header line, and give the labeler and product codes of each
+// drug, hyphenated, in the PRODUCTNDC column.  Their lengths give the
+// layout.  The following errors can be returned in addition to read
+// and CSV errors:
+//
+//	ErrLabelerTable
+func LoadLabelerTable(r io.Reader) (LabelerTable, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = '\t'
+	cr.LazyQuotes = true
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, ErrLabelerTable
+	} else if err != nil {
+		return nil, err
+	}
+	col := -1
+	for i, name := range header {
+		if strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")) == "PRODUCTNDC" {
+			col = i
+		}
+	}
+	if col < 0 {
+		return nil, ErrLabelerTable
+	}
+
+	t := make(LabelerTable)
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if col >= len(rec) {
+			return nil, ErrLabelerTable
+		}
+		labeler, product, ok := strings.Cut(strings.TrimSpace(rec[col]), "-")
+		if !ok || !isDigits(labeler) || !isDigits(product) {
+			return nil, ErrLabelerTable
+		}
+		var l NdcLayout
+		switch {
+		case len(labeler) == 4 && len(product) == 4:
+			l = Ndc442
+		case len(labeler) == 5 && len(product) == 3:
+			l = Ndc532
+		case len(labeler) == 5 && len(product) == 4:
+			l = Ndc541
+		default:
+			return nil, ErrLabelerTable
+		}
+		t[labeler] = l
+	}
+	return t, nil
+}
+
+// NdcLayout looks up the 5-digit labeler code, then the 4-digit
+// labeler code, of a 10-digit NDC.
+func (t LabelerTable) NdcLayout(digits string) (NdcLayout, bool) {
+	if len(digits) >= 5 {
+		if l, ok := t[digits[:5]]; ok && l != Ndc442 {
+			return l, true
+		}
+	}
+	if len(digits) >= 4 {
+		if l, ok := t[digits[:4]]; ok && l == Ndc442 {
+			return l, true
+		}
+	}
+	return 0, false
+}
+
+// Ndc represents a National Drug Code.  The segments are strings
+// because leading zeros are meaningful in the FDA database.
+type Ndc struct {
+	Labeler string
+	Product string
+	Package string
+}
+
+var ErrNdcFormat = errors.New("NDC must be 10 digits in a 4-4-2, 5-3-2 or 5-4-1 layout, or 11 digits in the 5-4-2 layout")
+var ErrNdcAmbiguous = errors.New("NDC layout can't be determined without a labeler table")
+var ErrNotDrug = errors.New("UPC is not a drug code (must be number system 3)")
+
+// ParseNdc parses a National Drug Code.  It accepts the hyphenated
+// 4-4-2, 5-3-2 and 5-4-1 layouts, and the 11-digit 5-4-2 billing
+// format with or without hyphens.  An unhyphenated 10-digit NDC could
+// be in any layout, so r is used to resolve it.  r is also used when
+// several layouts could have been padded into the same 11-digit code.
+// r may be nil.  The following errors can be returned in addition to
+// integer parsing errors:
+//
+//	ErrNdcFormat
+//	ErrNdcAmbiguous
+func ParseNdc(s string, r NdcResolver) (Ndc, error) {
+	for _, b := range []byte(s) {
+		if (b < 48 || b > 57) && b != '-' {
+			return Ndc{}, fmt.Errorf("Invalid NDC digit: %c", b)
+		}
+	}
+
+	parts := strings.Split(s, "-")
+	switch len(parts) {
+	case 1:
+		switch len(s) {
+		case 10:
+			if r == nil {
+				return Ndc{}, ErrNdcAmbiguous
+			}
+			l, ok := r.NdcLayout(s)
+			if !ok {
+				return Ndc{}, ErrNdcAmbiguous
+			}
+			return splitNdc(s, l), nil
+		case 11:
+			return parseNdc11(s[:5], s[5:9], s[9:], r)
+		}
+	case 3:
+		n := Ndc{parts[0], parts[1], parts[2]}
+		if n.Layout() != 0 {
+			return n, nil
+		}
+		if len(parts[0]) == 5 && len(parts[1]) == 4 && len(parts[2]) == 2 {
+			return parseNdc11(parts[0], parts[1], parts[2], r)
+		}
+	}
+	return Ndc{}, ErrNdcFormat
+}
+
+// parseNdc11 converts the segments of an 11-digit NDC back into the
+// 10-digit layout it was padded from.
+func parseNdc11(labeler, product, pkg string, r NdcResolver) (Ndc, error) {
+	var candidates []Ndc
+	if labeler[0] == '0' {
+		candidates = append(candidates, Ndc{labeler[1:], product, pkg})
+	}
+	if product[0] == '0' {
+		candidates = append(candidates, Ndc{labeler, product[1:], pkg})
+	}
+	if pkg[0] == '0' {
+		candidates = append(candidates, Ndc{labeler, product, pkg[1:]})
+	}
+
+	switch {
+	case len(candidates) == 0:
+		return Ndc{}, ErrNdcFormat
+	case len(candidates) == 1:
+		return candidates[0], nil
+	case r == nil:
+		return Ndc{}, ErrNdcAmbiguous
+	}
+	for _, n := range candidates {
+		if l, ok := r.NdcLayout(n.Digits()); ok && l == n.Layout() {
+			return n, nil
+		}
+	}
+	return Ndc{}, ErrNdcAmbiguous
+}
+
+// splitNdc splits 10 digits into the segments of a layout.
+func splitNdc(digits string, l NdcLayout) Ndc {
+	a, b, _ := l.segments()
+	return Ndc{digits[:a], digits[a : a+b], digits[a+b:]}
+}
+
+// Layout returns the segment layout of the NDC, or 0 if the segments
+// don't have the lengths of a 10-digit NDC.
+func (n Ndc) Layout() NdcLayout {
+	for _, l := range []NdcLayout{Ndc442, Ndc532, Ndc541} {
+		a, b, c := l.segments()
+		if len(n.Labeler) == a && len(n.Product) == b && len(n.Package) == c {
+			return l
+		}
+	}
+	return 0
+}
+
+// String returns the NDC in its hyphenated, 10-digit form.
+func (n Ndc) String() string {
+	return n.Labeler + "-" + n.Product + "-" + n.Package
+}
+
+// Digits returns the 10 digits of the NDC without hyphens.
+func (n Ndc) Digits() string {
+	return n.Labeler + n.Product + n.Package
+}
+
+// Ndc11 returns the 11-digit, 5-4-2 form of the NDC used in billing
+// and claims, without hyphens.  Each segment is padded with a leading
+// zero to its 5-4-2 length.
+func (n Ndc) Ndc11() string {
+	return padNdc(n.Labeler, 5) + padNdc(n.Product, 4) + padNdc(n.Package, 2)
+}
+
+// padNdc pads an NDC segment with leading zeros.
+func padNdc(segment string, length int) string {
+	if len(segment) >= length {
+		return segment
+	}
+	return strings.Repeat("0", length-len(segment)) + segment
+}
+
+// Upc returns the number system 3 UPC which encodes this NDC.
+func (n Ndc) Upc() Upc {
+	var u int64 = 3
+	for _, b := range []byte(n.Digits()) {
+		u *= 10
+		u += int64(b - 48)
+	}
+	return Upc(u)
+}
+
+// Gtin returns the GTIN-12 which encodes this NDC.  Use CaseGtin on
+// the result for packaging levels.
+func (n Ndc) Gtin() Gtin {
+	return n.Upc().Gtin()
+}
+
+// NdcSegments returns the National Drug Code of a number system 3 UPC
+// split into segments.  The UPC only carries 10 digits, so r is needed
+// to determine the layout.  The following errors can be returned:
+//
+//	ErrNotDrug
+//	ErrNdcAmbiguous
+func (u Upc) NdcSegments(r NdcResolver) (Ndc, error) {
+	if !u.IsDrug() {
+		return Ndc{}, ErrNotDrug
+	}
+	if r == nil {
+		return Ndc{}, ErrNdcAmbiguous
+	}
+	digits := u.Ndc()
+	l, ok := r.NdcLayout(digits)
+	if !ok {
+		return Ndc{}, ErrNdcAmbiguous
+	}
+	return splitNdc(digits, l), nil
+}
@@ -0,0 +1,140 @@
+package upc
+
+import (
+	"strings"
+	"testing"
+)
+
+var labelers = LabelerTable{
+	"0002":  Ndc442, // Eli Lilly
+	"63824": Ndc532, // Reckitt Benckiser
+	"50580": Ndc541,
+	"12345": Ndc532,
+}
+
+// the expected forms of an NDC
+type ndcBreakdown struct {
+	hyphenated string
+	layout     NdcLayout
+	ndc11      string
+	upc        string
+}
+
+var ndcTests = map[string]ndcBreakdown{
+	"0002-3227-30":  {"0002-3227-30", Ndc442, "00002322730", "300023227300"},
+	"63824-057-36":  {"63824-057-36", Ndc532, "63824005736", "363824057361"},
+	"50580-0449-1":  {"50580-0449-1", Ndc541, "50580044901", "350580044919"},
+	"00002-3227-30": {"0002-3227-30", Ndc442, "00002322730", "300023227300"},
+	"63824-0057-36": {"63824-057-36", Ndc532, "63824005736", "363824057361"},
+	"50580-0449-01": {"50580-0449-1", Ndc541, "50580044901", "350580044919"},
+	"00002322730":   {"0002-3227-30", Ndc442, "00002322730", "300023227300"},
+	"6382405736":    {"63824-057-36", Ndc532, "63824005736", "363824057361"},
+	"12345-0678-09": {"12345-678-09", Ndc532, "12345067809", "312345678098"},
+}
+
+func TestNdc(t *testing.T) {
+	for s, expect := range ndcTests {
+		n, err := ParseNdc(s, labelers)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		got := ndcBreakdown{n.String(), n.Layout(), n.Ndc11(), n.Upc().String()}
+		if got != expect {
+			t.Errorf("%s: wrong breakdown\n got: %#v\nwant: %#v\n", s, got, expect)
+		}
+		if g := n.Gtin(); g.String() != expect.upc {
+			t.Errorf("%s: wrong GTIN: got %s", s, g)
+		}
+	}
+}
+
+func TestNdcSegments(t *testing.T) {
+	u, _ := Parse("363824057361") // Mucinex D
+	n, err := u.NdcSegments(labelers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.String() != "63824-057-36" {
+		t.Errorf("%s: wrong NDC: got %s", u, n)
+	}
+	if n.Upc() != u {
+		t.Errorf("%s: wrong round trip: got %s", u, n.Upc())
+	}
+
+	if _, err := u.NdcSegments(nil); err != ErrNdcAmbiguous {
+		t.Errorf("%s: expected ErrNdcAmbiguous got %q", u, err)
+	}
+	if _, err := u.NdcSegments(LabelerTable{}); err != ErrNdcAmbiguous {
+		t.Errorf("%s: expected ErrNdcAmbiguous got %q", u, err)
+	}
+	u, _ = Parse("045496830434")
+	if _, err := u.NdcSegments(labelers); err != ErrNotDrug {
+		t.Errorf("%s: expected ErrNotDrug got %q", u, err)
+	}
+}
+
+// the first columns of the FDA's product.txt
+const fdaProducts = "PRODUCTID\tPRODUCTNDC\tPRODUCTTYPENAME\tPROPRIETARYNAME\n" +
+	"0002-3227_8e3c1e2a\t0002-3227\tHUMAN PRESCRIPTION DRUG\tStrattera\n" +
+	"63824-057_0c4a8b3f\t63824-057\tHUMAN OTC DRUG\tMucinex D\n" +
+	"50580-449_a7d9e1c0\t50580-0449\tHUMAN OTC DRUG\tTylenol\n"
+
+func TestLoadLabelerTable(t *testing.T) {
+	table, err := LoadLabelerTable(strings.NewReader(fdaProducts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := LabelerTable{"0002": Ndc442, "63824": Ndc532, "50580": Ndc541}
+	if len(table) != len(want) {
+		t.Errorf("wrong table: got %v", table)
+	}
+	for labeler, l := range want {
+		if table[labeler] != l {
+			t.Errorf("%s: wrong layout: got %s", labeler, table[labeler])
+		}
+	}
+	if n, err := ParseNdc("5058004491", table); err != nil || n.String() != "50580-0449-1" {
+		t.Errorf("wrong NDC: got %s, %v", n, err)
+	}
+
+	for _, data := range []string{
+		"",
+		"PRODUCTID\tNDC\n0002-3227_8e3c1e2a\t0002-3227\n",
+		"PRODUCTID\tPRODUCTNDC\n0002-3227_8e3c1e2a\t00023227\n",
+		"PRODUCTID\tPRODUCTNDC\n0002-3227_8e3c1e2a\t002-3227\n",
+		"PRODUCTID\tPRODUCTNDC\n0002-3227_8e3c1e2a\t0002-32X7\n",
+		"PRODUCTID\tPRODUCTNDC\n0002-3227_8e3c1e2a\n",
+	} {
+		if _, err := LoadLabelerTable(strings.NewReader(data)); err != ErrLabelerTable {
+			t.Errorf("%q: expected ErrLabelerTable got %v", data, err)
+		}
+	}
+}
+
+func TestNdcWrong(t *testing.T) {
+	errs := map[string]error{
+		"":              ErrNdcFormat,
+		"123456789":     ErrNdcFormat,
+		"123456789012":  ErrNdcFormat,
+		"6382-4057-361": ErrNdcFormat,
+		"63824-57-36":   ErrNdcFormat,
+		"63824-1057-36": ErrNdcFormat,
+		"1234567890":    ErrNdcAmbiguous,
+		"12345-0678-01": ErrNdcAmbiguous,
+	}
+	for s, want := range errs {
+		if _, err := ParseNdc(s, nil); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+	if _, err := ParseNdc("63824-05x-36", nil); err == nil {
+		t.Errorf("expected an error got none")
+	}
+
+	// resolved only with a labeler table
+	n, err := ParseNdc("12345-0678-01", labelers)
+	if err != nil || n.String() != "12345-678-01" {
+		t.Errorf("wrong NDC: got %s, %v", n, err)
+	}
+}
@@ -0,0 +1,197 @@
+package upc
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Company is the owner of a company prefix in a Registry.
+type Company struct {
+	Prefix string `json:"prefix"` // digits at the start of a GTIN-13
+	Name   string `json:"name"`
+	Brand  string `json:"brand,omitempty"`
+}
+
+// Registry resolves GTINs to the companies which own them, by the
+// longest company prefix matching the GTIN-13.  A U.P.C. company
+// prefix is registered with a leading zero, e.g. 0045496.  A Registry
+// is safe for concurrent use, and loading replaces its contents
+// atomically, so lookups can continue during a reload.
+type Registry struct {
+	mu        sync.RWMutex
+	companies map[string]Company
+	path      string
+}
+
+var ErrRegistryRecord = errors.New("company registry record must have a prefix of 1 to 12 digits and a name")
+var ErrRegistryPath = errors.New("company registry wasn't loaded from a file")
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{companies: make(map[string]Company)}
+}
+
+// LoadCsv replaces the contents of the registry with CSV records of a
+// prefix, a name and an optional brand.  Spaces around fields are
+// ignored.  A first line is taken as a header and skipped if its first
+// field has no digits, as in "prefix,name,brand".  The registry is
+// unchanged if an error is returned.  The following errors can be
+// returned in addition to CSV errors:
+//
+//	ErrRegistryRecord
+func (r *Registry) LoadCsv(rd io.Reader) error {
+	cr := csv.NewReader(rd)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+	for _, rec := range records {
+		for i := range rec {
+			rec[i] = strings.TrimSpace(rec[i])
+		}
+	}
+	if len(records) > 0 && isCsvHeader(records[0]) {
+		records = records[1:]
+	}
+
+	var companies []Company
+	for _, rec := range records {
+		c := Company{Prefix: rec[0]}
+		if len(rec) > 1 {
+			c.Name = rec[1]
+		}
+		if len(rec) > 2 {
+			c.Brand = rec[2]
+		}
+		companies = append(companies, c)
+	}
+	return r.replace(companies)
+}
+
+// isCsvHeader returns true if a CSV record is a header line, rather
+// than a company whose prefix is mistyped, such as O045496.
+func isCsvHeader(rec []string) bool {
+	return len(rec) > 0 && rec[0] != "" && strings.IndexAny(rec[0], "0123456789") < 0
+}
+
+// LoadJson replaces the contents of the registry with a JSON array of
+// companies, as in [{"prefix": "0045496", "name": "Nintendo"}].  The
+// registry is unchanged if an error is returned.  The following errors
+// can be returned in addition to JSON errors:
+//
+//	ErrRegistryRecord
+func (r *Registry) LoadJson(rd io.Reader) error {
+	var companies []Company
+	if err := json.NewDecoder(rd).Decode(&companies); err != nil {
+		return err
+	}
+	return r.replace(companies)
+}
+
+// LoadFile replaces the contents of the registry with a CSV or JSON
+// file, depending on its extension, and remembers the path for Reload.
+func (r *Registry) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = r.LoadJson(f)
+	} else {
+		err = r.LoadCsv(f)
+	}
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.path = path
+	r.mu.Unlock()
+	return nil
+}
+
+// Reload loads the file last given to LoadFile again, so the registry
+// picks up changes without a restart.  The registry is unchanged if
+// an error is returned.
+func (r *Registry) Reload() error {
+	r.mu.RLock()
+	path := r.path
+	r.mu.RUnlock()
+	if path == "" {
+		return ErrRegistryPath
+	}
+	return r.LoadFile(path)
+}
+
+// replace validates companies and swaps them into the registry.
+func (r *Registry) replace(companies []Company) error {
+	m := make(map[string]Company, len(companies))
+	for _, c := range companies {
+		c.Prefix = strings.TrimSpace(c.Prefix)
+		if len(c.Prefix) < 1 || len(c.Prefix) > 12 || !isDigits(c.Prefix) || c.Name == "" {
+			return ErrRegistryRecord
+		}
+		m[c.Prefix] = c
+	}
+
+	r.mu.Lock()
+	r.companies = m
+	r.mu.Unlock()
+	return nil
+}
+
+// isDigits returns true if s is made only of digits.
+func isDigits(s string) bool {
+	for _, b := range []byte(s) {
+		if b < 48 || b > 57 {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Len returns the number of companies in the registry.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.companies)
+}
+
+// lookup finds the company with the longest prefix of a GTIN-13.
+func (r *Registry) lookup(gtin13 string) (Company, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for n := 12; n > 0; n-- {
+		if c, ok := r.companies[gtin13[:n]]; ok {
+			return c, true
+		}
+	}
+	return Company{}, false
+}
+
+// Ean returns the company which owns the EAN.  The second return value
+// is false if no prefix matches.
+func (r *Registry) Ean(e Ean) (Company, bool) {
+	return r.lookup(e.String())
+}
+
+// Upc returns the company which owns the UPC.  The second return value
+// is false if no prefix matches.
+func (r *Registry) Upc(u Upc) (Company, bool) {
+	return r.lookup("0" + u.String())
+}
+
+// Gtin returns the company which owns the GTIN, ignoring its
+// indicator digit.  The second return value is false if no prefix
+// matches.
+func (r *Registry) Gtin(g Gtin) (Company, bool) {
+	return r.lookup(g.Gtin14()[1:])
+}
@@ -0,0 +1,154 @@
+package upc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const registryCsv = `prefix,name,brand
+0045496,Nintendo,
+4902370,Nintendo,Nintendo Japan
+0711719,Sony,PlayStation
+07117195,Sony,PlayStation VR
+`
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	if err := r.LoadCsv(strings.NewReader(registryCsv)); err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 4 {
+		t.Errorf("wrong length: got %d", r.Len())
+	}
+
+	u, _ := Parse("045496830434")
+	if c, ok := r.Upc(u); !ok || c.Name != "Nintendo" {
+		t.Errorf("%s: wrong company: got %#v", u, c)
+	}
+	e, _ := ParseEan("4902370536485")
+	if c, ok := r.Ean(e); !ok || c.Brand != "Nintendo Japan" {
+		t.Errorf("%s: wrong company: got %#v", e, c)
+	}
+
+	// the longest prefix wins
+	u, _ = Parse("711719541028")
+	if c, ok := r.Upc(u); !ok || c.Brand != "PlayStation VR" {
+		t.Errorf("%s: wrong company: got %#v", u, c)
+	}
+	u, _ = Parse("711719876540")
+	if c, ok := r.Gtin(u.Gtin()); !ok || c.Brand != "PlayStation" {
+		t.Errorf("%s: wrong company: got %#v", u, c)
+	}
+
+	e, _ = ParseEan("5012345678900")
+	if _, ok := r.Ean(e); ok {
+		t.Errorf("%s: expected no company", e)
+	}
+}
+
+func TestRegistryCsvSpaces(t *testing.T) {
+	// without a header, and a first prefix indented
+	r := NewRegistry()
+	if err := r.LoadCsv(strings.NewReader(" 0045496, Nintendo\n4902370 ,Nintendo, Nintendo Japan \n")); err != nil {
+		t.Fatal(err)
+	}
+	if r.Len() != 2 {
+		t.Errorf("wrong length: got %d", r.Len())
+	}
+	u, _ := Parse("045496830434")
+	if c, ok := r.Upc(u); !ok || c.Name != "Nintendo" {
+		t.Errorf("%s: wrong company: got %#v", u, c)
+	}
+	e, _ := ParseEan("4902370536485")
+	if c, ok := r.Ean(e); !ok || c.Brand != "Nintendo Japan" {
+		t.Errorf("%s: wrong company: got %#v", e, c)
+	}
+}
+
+func TestRegistryJson(t *testing.T) {
+	r := NewRegistry()
+	err := r.LoadJson(strings.NewReader(`[
+		{"prefix": "0045496", "name": "Nintendo"},
+		{"prefix": "0711719", "name": "Sony", "brand": "PlayStation"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := Parse("711719541028")
+	if c, ok := r.Upc(u); !ok || c.Name != "Sony" || c.Prefix != "0711719" {
+		t.Errorf("%s: wrong company: got %#v", u, c)
+	}
+}
+
+func TestRegistryReload(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Reload(); err != ErrRegistryPath {
+		t.Errorf("expected ErrRegistryPath got %q", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "companies.csv")
+	if err := os.WriteFile(path, []byte("0045496,Nintendo\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.LoadFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	u, _ := Parse("045496830434")
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, ok := r.Upc(u); !ok {
+					t.Errorf("%s: lookup failed during reload", u)
+					return
+				}
+			}
+		}()
+	}
+	if err := os.WriteFile(path, []byte("0045496,Nintendo of America\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if c, _ := r.Upc(u); c.Name != "Nintendo of America" {
+		t.Errorf("%s: reload didn't take effect: got %#v", u, c)
+	}
+
+	// a bad file leaves the registry alone
+	if err := os.WriteFile(path, []byte("0045496\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err != ErrRegistryRecord {
+		t.Errorf("expected ErrRegistryRecord got %q", err)
+	}
+	if c, _ := r.Upc(u); c.Name != "Nintendo of America" {
+		t.Errorf("%s: failed reload changed the registry: got %#v", u, c)
+	}
+}
+
+func TestRegistryWrong(t *testing.T) {
+	records := []string{
+		"0045496,\n",
+		"0045496,Nintendo\n004549x,Sony\n",
+		"0045496,Nintendo\n,Sony\n",
+		"0045496830434,Nintendo\n",
+		"O045496,Nintendo\n4902370,Nintendo\n",
+		",Nintendo\n4902370,Nintendo\n",
+	}
+	for _, data := range records {
+		if err := NewRegistry().LoadCsv(strings.NewReader(data)); err != ErrRegistryRecord {
+			t.Errorf("%q: expected ErrRegistryRecord got %q", data, err)
+		}
+	}
+	if err := NewRegistry().LoadJson(strings.NewReader(`{}`)); err == nil {
+		t.Errorf("expected an error got none")
+	}
+}
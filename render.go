@@ -0,0 +1,367 @@
+package upc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RenderOptions controls the size and content of a rendered barcode.
+// Lengths are in millimetres.  Zero fields take the GS1 nominal value
+// for the symbology at 100% magnification.
+type RenderOptions struct {
+	ModuleWidth    float64 // width of a module, the X-dimension; 0.33, or 1.016 for ITF-14
+	BarHeight      float64 // height of the normal bars; 22.85, 18.23 for EAN-8 or 32 for ITF-14
+	GuardExtension float64 // extra height of guard bars; 5 modules
+	BearerWidth    float64 // thickness of the bearer bars around an ITF-14; 4.8
+	QuietZone      int     // width of each quiet zone in modules, if more than the standard
+	NoText         bool    // omit the human readable digits
+	DPI            float64 // resolution of images, to which modules are rounded; 300
+}
+
+// defaults returns the options with zero fields filled in.
+func (o *RenderOptions) defaults(f SymbolFormat) RenderOptions {
+	var r RenderOptions
+	if o != nil {
+		r = *o
+	}
+	if r.ModuleWidth <= 0 {
+		r.ModuleWidth = 0.33
+		if f == SymbolItf14 {
+			r.ModuleWidth = 1.016
+		}
+	}
+	if r.BarHeight <= 0 {
+		switch f {
+		case SymbolEan8:
+			r.BarHeight = 18.23 * r.ModuleWidth / 0.33
+		case SymbolItf14:
+			r.BarHeight = 32
+		default:
+			r.BarHeight = 22.85 * r.ModuleWidth / 0.33
+		}
+	}
+	if r.GuardExtension <= 0 {
+		r.GuardExtension = 5 * r.ModuleWidth
+	}
+	if r.BearerWidth <= 0 {
+		r.BearerWidth = 4.8
+	}
+	if r.DPI <= 0 {
+		r.DPI = 300
+	}
+	return r
+}
+
+// Heights of the human readable digits and of the gap above them, in
+// modules.
+const (
+	textHeight = 8
+	textGap    = 0.5
+)
+
+// layout is a barcode drawn as rectangles and digits, in millimetres.
+type layout struct {
+	width, height float64
+	bars          []rect
+	digits        []glyph
+}
+
+// rect is a bar.
+type rect struct {
+	x, y, w, h float64
+}
+
+// glyph is a human readable digit centred on x, with its top at y.
+type glyph struct {
+	digit byte
+	x, y  float64
+	h     float64
+}
+
+// layout positions the bars and digits of the symbol.
+func (s Symbol) layout(o RenderOptions) layout {
+	x := o.ModuleWidth
+	left, right := s.QuietLeft, s.QuietRight
+	if o.QuietZone > left {
+		left = o.QuietZone
+	}
+	if o.QuietZone > right {
+		right = o.QuietZone
+	}
+	modules := s.Modules[s.QuietLeft : len(s.Modules)-s.QuietRight]
+	guards := s.Guards[s.QuietLeft : len(s.Guards)-s.QuietRight]
+	addOnStart := -1
+	if s.AddOnStart > 0 {
+		addOnStart = s.AddOnStart - s.QuietLeft
+	}
+
+	l := layout{
+		width:  float64(left+len(modules)+right) * x,
+		height: o.BarHeight + o.GuardExtension,
+	}
+	if !o.NoText && o.BarHeight+(textGap+textHeight)*x > l.height {
+		l.height = o.BarHeight + (textGap+textHeight)*x
+	}
+
+	// runs of bar modules of the same height become one rectangle
+	for i := 0; i < len(modules); {
+		if !modules[i] {
+			i++
+			continue
+		}
+		j := i
+		for j < len(modules) && modules[j] && guards[j] == guards[i] {
+			j++
+		}
+		r := rect{x: float64(left+i) * x, w: float64(j-i) * x, h: o.BarHeight}
+		switch {
+		case addOnStart >= 0 && i >= addOnStart:
+			if !o.NoText {
+				r.y = (textHeight + textGap) * x
+			}
+			r.h = o.BarHeight + o.GuardExtension - r.y
+		case guards[i]:
+			r.h = o.BarHeight + o.GuardExtension
+		}
+		l.bars = append(l.bars, r)
+		i = j
+	}
+
+	if !o.NoText {
+		s.placeDigits(&l, o, left, addOnStart)
+	}
+	if s.Format == SymbolItf14 {
+		l.bearers(o)
+	}
+	return l
+}
+
+// placeDigits positions the human readable digits below the bars, given
+// the quiet zone left of them and the module of the add-on, if any.
+func (s Symbol) placeDigits(l *layout, o RenderOptions, left, addOnStart int) {
+	x := o.ModuleWidth
+	top := o.BarHeight + textGap*x
+	at := func(d byte, module float64) {
+		l.digits = append(l.digits, glyph{d, float64(left)*x + module*x, top, textHeight * x})
+	}
+	digits := s.Digits
+	switch s.Format {
+	case SymbolUpcA:
+		at(digits[0], -4.5)
+		for i := 1; i < 6; i++ {
+			at(digits[i], 3+7*float64(i)+3.5)
+		}
+		for i := 6; i < 11; i++ {
+			at(digits[i], 50+7*float64(i-6)+3.5)
+		}
+		at(digits[11], 95+4.5)
+	case SymbolEan13:
+		at(digits[0], -5.5)
+		for i := 1; i < 7; i++ {
+			at(digits[i], 3+7*float64(i-1)+3.5)
+		}
+		for i := 7; i < 13; i++ {
+			at(digits[i], 50+7*float64(i-7)+3.5)
+		}
+	case SymbolUpcE:
+		at(digits[0], -4.5)
+		for i := 1; i < 7; i++ {
+			at(digits[i], 3+7*float64(i-1)+3.5)
+		}
+		at(digits[7], 51+3.5)
+	case SymbolEan8:
+		for i := 0; i < 4; i++ {
+			at(digits[i], 3+7*float64(i)+3.5)
+		}
+		for i := 4; i < 8; i++ {
+			at(digits[i], 36+7*float64(i-4)+3.5)
+		}
+	case SymbolItf14:
+		// each pair of digits is 18 modules, after the 4 of the start
+		for i := 0; i < 14; i++ {
+			at(digits[i], 4+9*float64(i)+4.5)
+		}
+	}
+
+	// add-on digits go above its bars
+	top = 0
+	for i := range s.AddOn {
+		at(s.AddOn[i], float64(addOnStart+4+9*i)+3.5)
+	}
+}
+
+// bearers surrounds the bars and quiet zones of an ITF-14 with bearer
+// bars, which even out the pressure of the printing plate on the bars
+// and keep a scanline which leaves the barcode through its top or
+// bottom from reading a short code.  The digits go below the bottom
+// bearer bar.
+func (l *layout) bearers(o RenderOptions) {
+	w := o.BearerWidth
+	for i := range l.bars {
+		l.bars[i].x += w
+		l.bars[i].y += w
+	}
+	for i := range l.digits {
+		l.digits[i].x += w
+		l.digits[i].y += 2 * w
+	}
+	l.width += 2 * w
+	l.height = o.BarHeight + 2*w
+	if len(l.digits) > 0 {
+		l.height += (textGap + textHeight) * o.ModuleWidth
+	}
+	l.bars = append(l.bars,
+		rect{0, 0, l.width, w},
+		rect{0, w + o.BarHeight, l.width, w},
+		rect{0, 0, w, o.BarHeight + 2*w},
+		rect{l.width - w, 0, w, o.BarHeight + 2*w})
+}
+
+// SVG returns the symbol as an SVG image sized in millimetres.  opts
+// may be nil for the default options.
+func (s Symbol) SVG(opts *RenderOptions) string {
+	o := opts.defaults(s.Format)
+	l := s.layout(o)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%smm" height="%smm" viewBox="0 0 %s %s">`,
+		svgNumber(l.width), svgNumber(l.height), svgNumber(l.width), svgNumber(l.height))
+	b.WriteString("\n" + `<rect width="100%" height="100%" fill="#fff"/>` + "\n")
+	b.WriteString(`<g fill="#000">` + "\n")
+	for _, r := range l.bars {
+		fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s"/>`+"\n",
+			svgNumber(r.x), svgNumber(r.y), svgNumber(r.w), svgNumber(r.h))
+	}
+	for _, g := range l.digits {
+		// a digit's height is about 0.7 of the font size
+		fmt.Fprintf(&b, `<text x="%s" y="%s" font-family="OCR-B, monospace" font-size="%s" text-anchor="middle">%c</text>`+"\n",
+			svgNumber(g.x), svgNumber(g.y+g.h), svgNumber(g.h/0.7), g.digit)
+	}
+	b.WriteString("</g>\n</svg>\n")
+	return b.String()
+}
+
+// svgNumber formats a length with at most 3 decimals.
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+// Image returns the symbol drawn in black on white at the resolution
+// of the options.  The module width is rounded to a whole number of
+// pixels so every bar has sharp edges.  opts may be nil for the
+// default options.
+func (s Symbol) Image(opts *RenderOptions) *image.Gray {
+	img, _ := s.draw(opts.defaults(s.Format))
+	return img
+}
+
+// draw returns the symbol drawn as described by Image, and its scale in
+// pixels per millimetre.
+func (s Symbol) draw(o RenderOptions) (*image.Gray, float64) {
+	l := s.layout(o)
+
+	px := math.Round(o.ModuleWidth * o.DPI / 25.4)
+	if px < 1 {
+		px = 1
+	}
+	scale := px / o.ModuleWidth // pixels per millimetre
+	p := func(v float64) int { return int(math.Round(v * scale)) }
+
+	img := image.NewGray(image.Rect(0, 0, p(l.width), p(l.height)))
+	fill(img, img.Bounds(), color.Gray{255})
+	for _, r := range l.bars {
+		fill(img, image.Rect(p(r.x), p(r.y), p(r.x+r.w), p(r.y+r.h)), color.Gray{0})
+	}
+	for _, g := range l.digits {
+		drawDigit(img, g.digit, p(g.x), p(g.y), p(g.h))
+	}
+	return img, scale
+}
+
+// WritePNG writes the symbol as a PNG image.  See Image.  The image
+// records its resolution in a pHYs chunk, so it prints at the size of
+// the options.  That's close to DPI, but not always equal to it, since
+// a module is a whole number of pixels.
+func (s Symbol) WritePNG(w io.Writer, opts *RenderOptions) error {
+	img, scale := s.draw(opts.defaults(s.Format))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+
+	// the chunk must come after the signature and the IHDR chunk
+	data := buf.Bytes()
+	head := 8 + 4 + 4 + 13 + 4
+	if _, err := w.Write(data[:head]); err != nil {
+		return err
+	}
+	if _, err := w.Write(physChunk(scale * 1000)); err != nil {
+		return err
+	}
+	_, err := w.Write(data[head:])
+	return err
+}
+
+// physChunk returns a PNG pHYs chunk giving a resolution in pixels per
+// metre along both axes.
+func physChunk(ppm float64) []byte {
+	c := make([]byte, 4+4+9+4)
+	binary.BigEndian.PutUint32(c[0:], 9)
+	copy(c[4:], "pHYs")
+	binary.BigEndian.PutUint32(c[8:], uint32(math.Round(ppm)))
+	binary.BigEndian.PutUint32(c[12:], uint32(math.Round(ppm)))
+	c[16] = 1 // the unit is the metre
+	binary.BigEndian.PutUint32(c[17:], crc32.ChecksumIEEE(c[4:17]))
+	return c
+}
+
+// fill paints a rectangle of the image.
+func fill(img *image.Gray, r image.Rectangle, c color.Gray) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetGray(x, y, c)
+		}
+	}
+}
+
+// drawDigit draws a digit of the bitmap font centred on x, with its top
+// at y and a height of h pixels.
+func drawDigit(img *image.Gray, d byte, x, y, h int) {
+	rows := digitFont[d-48]
+	cell := h / len(rows)
+	if cell < 1 {
+		cell = 1
+	}
+	left := x - len(rows[0])*cell/2
+	for i, row := range rows {
+		for j := range row {
+			if row[j] == '#' {
+				fill(img, image.Rect(left+j*cell, y+i*cell, left+(j+1)*cell, y+(i+1)*cell), color.Gray{0})
+			}
+		}
+	}
+}
+
+// digitFont is a 5 by 7 bitmap font of the digits.
+var digitFont = [10][7]string{
+	{".###.", "#...#", "#..##", "#.#.#", "##..#", "#...#", ".###."},
+	{"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."},
+	{".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"},
+	{"#####", "...#.", "..#..", "...#.", "....#", "#...#", ".###."},
+	{"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."},
+	{"#####", "#....", "####.", "....#", "....#", "#...#", ".###."},
+	{"..##.", ".#...", "#....", "####.", "#...#", "#...#", ".###."},
+	{"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."},
+	{".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."},
+	{".###.", "#...#", "#...#", ".####", "....#", "...#.", ".##.."},
+}
@@ -0,0 +1,133 @@
+package upc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestSVG(t *testing.T) {
+	u, _ := Parse("036000291452")
+	svg := u.Symbol().SVG(nil)
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="37.29mm" height="25.655mm"`) {
+		t.Errorf("wrong size: %s", svg[:strings.IndexByte(svg, '\n')])
+	}
+	if n := strings.Count(svg, "<text"); n != 12 {
+		t.Errorf("wrong number of digits: got %d", n)
+	}
+	if !strings.Contains(svg, `text-anchor="middle">0</text>`) {
+		t.Errorf("missing number system digit")
+	}
+	// the background and the 30 bars of a UPC-A
+	if n := strings.Count(svg, "<rect"); n != 1+30 {
+		t.Errorf("wrong number of rectangles: got %d", n)
+	}
+
+	svg = u.Symbol().SVG(&RenderOptions{ModuleWidth: 0.5, BarHeight: 10, NoText: true, QuietZone: 20})
+	if !strings.Contains(svg, `width="67.5mm" height="12.5mm"`) {
+		t.Errorf("options ignored: %s", svg[:strings.IndexByte(svg, '\n')])
+	}
+	if strings.Contains(svg, "<text") {
+		t.Errorf("unexpected digits")
+	}
+}
+
+func TestPNG(t *testing.T) {
+	for _, s := range []string{"036000291452", "4006381333931", "96385074", "04252614", "036000291452 52495"} {
+		sym := symbolFor(t, s)
+		var buf bytes.Buffer
+		if err := sym.WritePNG(&buf, &RenderOptions{DPI: 300}); err != nil {
+			t.Fatal(err)
+		}
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// 0.33mm at 300 DPI rounds to 4 pixels per module
+		b := img.Bounds()
+		if b.Dx() != 4*len(sym.Modules) {
+			t.Errorf("%s: wrong width: got %d", s, b.Dx())
+		}
+		y := b.Dy() / 2
+		for i, m := range sym.Modules {
+			r, _, _, _ := img.At(4*i+2, y).RGBA()
+			if (r == 0) != m {
+				t.Errorf("%s: module %d: wrong colour", s, i)
+				break
+			}
+		}
+	}
+}
+
+func TestPNGResolution(t *testing.T) {
+	u, _ := Parse("036000291452")
+	var buf bytes.Buffer
+	if err := u.Symbol().WritePNG(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the chunk after IHDR, which is 4 pixels per 0.33mm module
+	c := buf.Bytes()[8+4+4+13+4:]
+	if n := binary.BigEndian.Uint32(c); n != 9 || string(c[4:8]) != "pHYs" {
+		t.Fatalf("expected a pHYs chunk got %q of %d bytes", c[4:8], n)
+	}
+	x, y := binary.BigEndian.Uint32(c[8:]), binary.BigEndian.Uint32(c[12:])
+	if x != 12121 || y != 12121 || c[16] != 1 {
+		t.Errorf("wrong resolution: got %d by %d per unit %d", x, y, c[16])
+	}
+	if crc := binary.BigEndian.Uint32(c[17:]); crc != crc32.ChecksumIEEE(c[4:17]) {
+		t.Errorf("wrong CRC: got %08x", crc)
+	}
+	if _, err := png.Decode(&buf); err != nil {
+		t.Error(err)
+	}
+}
+
+// symbolFor returns the symbol of a code in one of the string forms
+// this package parses.
+func symbolFor(t *testing.T, s string) Symbol {
+	if i := strings.IndexByte(s, ' '); i == 13 {
+		e, err := ParseEanWithAddOn(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e.Symbol()
+	} else if i > 0 {
+		u, err := ParseWithAddOn(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u.Symbol()
+	}
+	switch len(s) {
+	case 8:
+		if s[0] == '0' || s[0] == '1' {
+			e, err := ParseUpcE(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return e.Symbol()
+		}
+		e, err := ParseEan8(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e.Symbol()
+	case 12:
+		u, err := Parse(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u.Symbol()
+	default:
+		e, err := ParseEan(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e.Symbol()
+	}
+}
@@ -0,0 +1,186 @@
+package upc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Sscc represents a Serial Shipping Container Code, the 18-digit
+// identifier of a logistic unit such as a pallet.  It's made of an
+// extension digit, a GS1 company prefix, a serial reference and a check
+// digit.  To reduce memory consumption, it's stored as a 64-bit integer
+// without the check digit.
+type Sscc int64
+
+var ErrSsccTooShort = errors.New("SSCC is too short (must be 18 digits)")
+var ErrSsccTooLong = errors.New("SSCC is too long (must be 18 digits)")
+var ErrSsccInvalidCheckDigit = errors.New("SSCC has an invalid check digit")
+var ErrCompanyPrefixLength = errors.New("GS1 company prefix must be 4 to 12 digits")
+var ErrSsccExtension = errors.New("SSCC extension digit must be 0 to 9")
+var ErrSsccExhausted = errors.New("no serial references left for the SSCC company prefix")
+
+// ParseSscc parses a string into an Sscc value.  The following errors
+// can be returned in addition to integer parsing errors:
+//
+//	ErrSsccTooShort
+//	ErrSsccTooLong
+//	ErrSsccInvalidCheckDigit
+func ParseSscc(s string) (Sscc, error) {
+	if len(s) < 18 {
+		return 0, ErrSsccTooShort
+	}
+	if len(s) > 18 {
+		return 0, ErrSsccTooLong
+	}
+
+	var n int64
+	var check int
+	for i, b := range []byte(s) {
+		if b < 48 || b > 57 {
+			return 0, fmt.Errorf("Invalid SSCC digit: %c", b)
+		}
+		if i == 17 {
+			check = int(b - 48)
+		} else {
+			n *= 10
+			n += int64(b - 48)
+		}
+	}
+	c := Sscc(n)
+	if c.CheckDigit() != check {
+		return 0, ErrSsccInvalidCheckDigit
+	}
+
+	return c, nil
+}
+
+// String returns the standard, 18-digit string representation of this
+// SSCC.
+func (c Sscc) String() string {
+	return fmt.Sprintf("%017d%d", int64(c), c.CheckDigit())
+}
+
+// CheckDigit returns the check digit that should be used as the 18th
+// digit of the SSCC.  The weighting is the same as for a UPC, starting
+// with 3 on the rightmost digit.
+func (c Sscc) CheckDigit() int {
+	return Upc(c).CheckDigit()
+}
+
+// Extension returns the first digit of the SSCC, which the company
+// assigning it uses to extend the capacity of its serial references.
+func (c Sscc) Extension() int {
+	return int(c / 10000000000000000)
+}
+
+// Split returns the company prefix and the serial reference of the
+// SSCC, given the length of its company prefix.  The extension digit
+// is part of neither.  The following errors can be returned:
+//
+//	ErrCompanyPrefixLength
+func (c Sscc) Split(prefixLength int) (string, string, error) {
+	if prefixLength < 4 || prefixLength > 12 {
+		return "", "", ErrCompanyPrefixLength
+	}
+	digits := c.String()[1:17]
+	return digits[:prefixLength], digits[prefixLength:], nil
+}
+
+// NewSscc returns the SSCC made of an extension digit, a company
+// prefix and a serial reference.  The following errors can be
+// returned:
+//
+//	ErrSsccExtension
+//	ErrCompanyPrefixLength
+//	ErrSsccExhausted
+func NewSscc(extension int, companyPrefix string, serial int64) (Sscc, error) {
+	if extension < 0 || extension > 9 {
+		return 0, ErrSsccExtension
+	}
+	if len(companyPrefix) < 4 || len(companyPrefix) > 12 {
+		return 0, ErrCompanyPrefixLength
+	}
+	for _, b := range []byte(companyPrefix) {
+		if b < 48 || b > 57 {
+			return 0, fmt.Errorf("Invalid SSCC digit: %c", b)
+		}
+	}
+	digits := 16 - len(companyPrefix)
+	if serial < 0 || serial >= pow10(digits) {
+		return 0, ErrSsccExhausted
+	}
+	return Sscc(int64(extension)*pow10(16) + atoi64(companyPrefix)*pow10(digits) + serial), nil
+}
+
+// SsccCounter is a persistent source of serial references.  Next
+// returns a value never returned before, usually one more than the
+// last.
+type SsccCounter interface {
+	Next() (int64, error)
+}
+
+// SsccGenerator allocates sequential SSCCs from a company prefix and a
+// counter.
+type SsccGenerator struct {
+	Extension     int
+	CompanyPrefix string
+	Counter       SsccCounter
+}
+
+// Next allocates the next SSCC.  It returns the counter's errors and
+// those of NewSscc.  ErrSsccExhausted means the counter has run past
+// the serial references available to the company prefix.
+func (g *SsccGenerator) Next() (Sscc, error) {
+	serial, err := g.Counter.Next()
+	if err != nil {
+		return 0, err
+	}
+	return NewSscc(g.Extension, g.CompanyPrefix, serial)
+}
+
+// FileCounter is an SsccCounter which keeps its value in a text file.
+// The first value returned is 1 if the file doesn't exist.  It's safe
+// for concurrent use within a process, but not by several processes.
+type FileCounter struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileCounter returns a counter backed by the file at path.
+func NewFileCounter(path string) *FileCounter {
+	return &FileCounter{Path: path}
+}
+
+// Next increments the value in the file and returns it.  The new
+// value is written to a temporary file and renamed over the old one,
+// so a crash never leaves a truncated counter.
+func (f *FileCounter) Next() (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var n int64
+	b, err := os.ReadFile(f.Path)
+	switch {
+	case err == nil:
+		n, err = strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil {
+			return 0, err
+		}
+	case !os.IsNotExist(err):
+		return 0, err
+	}
+	n++
+
+	tmp := f.Path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatInt(n, 10)+"\n"), 0644); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, f.Path); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,91 @@
+package upc
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSscc(t *testing.T) {
+	c, err := ParseSscc("106141411234567897")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.String() != "106141411234567897" {
+		t.Errorf("wrong string: got %s", c)
+	}
+	if c.CheckDigit() != 7 {
+		t.Errorf("wrong check digit: got %d", c.CheckDigit())
+	}
+	if c.Extension() != 1 {
+		t.Errorf("wrong extension: got %d", c.Extension())
+	}
+	prefix, serial, err := c.Split(7)
+	if err != nil || prefix != "0614141" || serial != "123456789" {
+		t.Errorf("wrong split: got %s %s, %v", prefix, serial, err)
+	}
+	if _, _, err := c.Split(13); err != ErrCompanyPrefixLength {
+		t.Errorf("expected ErrCompanyPrefixLength got %q", err)
+	}
+
+	c, err = ParseSscc("000000000000000000")
+	if err != nil || c.Extension() != 0 {
+		t.Errorf("wrong zero SSCC: got %s, %v", c, err)
+	}
+}
+
+func TestSsccWrong(t *testing.T) {
+	errs := map[string]error{
+		"10614141123456789":   ErrSsccTooShort,
+		"1061414112345678970": ErrSsccTooLong,
+		"106141411234567890":  ErrSsccInvalidCheckDigit,
+	}
+	for s, want := range errs {
+		if _, err := ParseSscc(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+	if _, err := ParseSscc("10614141123456789x"); err == nil {
+		t.Errorf("expected an error got none")
+	}
+}
+
+func TestNewSscc(t *testing.T) {
+	c, err := NewSscc(1, "0614141", 123456789)
+	if err != nil || c.String() != "106141411234567897" {
+		t.Errorf("wrong SSCC: got %s, %v", c, err)
+	}
+
+	errs := map[error]error{}
+	_, errs[ErrSsccExtension] = NewSscc(10, "0614141", 1)
+	_, errs[ErrCompanyPrefixLength] = NewSscc(1, "061", 1)
+	_, errs[ErrSsccExhausted] = NewSscc(1, "0614141", 1000000000)
+	for want, err := range errs {
+		if err != want {
+			t.Errorf("expected %q got %q", want, err)
+		}
+	}
+}
+
+func TestSsccGenerator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sscc")
+	g := &SsccGenerator{
+		Extension:     3,
+		CompanyPrefix: "061414112345",
+		Counter:       NewFileCounter(path),
+	}
+	for _, want := range []string{"306141411234500010", "306141411234500027"} {
+		c, err := g.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.String() != want {
+			t.Errorf("expected %s got %s", want, c)
+		}
+	}
+
+	// a new counter resumes from the file
+	g.Counter = NewFileCounter(path)
+	if c, _ := g.Next(); c.String()[:17] != "30614141123450003" {
+		t.Errorf("counter didn't resume: got %s", c)
+	}
+}
@@ -0,0 +1,278 @@
+package upc
+
+import "strings"
+
+// SymbolFormat is the barcode symbology of a Symbol.
+type SymbolFormat int
+
+const (
+	SymbolUpcA SymbolFormat = iota + 1
+	SymbolUpcE
+	SymbolEan13
+	SymbolEan8
+	SymbolItf14
+)
+
+// String returns the name of the symbology, e.g. EAN-13.
+func (f SymbolFormat) String() string {
+	switch f {
+	case SymbolUpcA:
+		return "UPC-A"
+	case SymbolUpcE:
+		return "UPC-E"
+	case SymbolEan13:
+		return "EAN-13"
+	case SymbolEan8:
+		return "EAN-8"
+	case SymbolItf14:
+		return "ITF-14"
+	default:
+		return "unknown"
+	}
+}
+
+// Symbol is a barcode as a sequence of modules, the narrowest bars and
+// spaces of the symbol.  Modules holds true for a bar and false for a
+// space, from left to right, including the quiet zones.  Any renderer
+// can draw it by giving each module the same width.
+type Symbol struct {
+	Format SymbolFormat
+	Digits string // all digits of the code, including the check digit
+	AddOn  string // digits of the add-on, if any
+
+	Modules []bool
+	Guards  []bool // modules of the bars extended below the others
+
+	QuietLeft  int // modules of quiet zone before the first bar
+	QuietRight int // modules of quiet zone after the last bar
+	AddOnStart int // index of the first module of the add-on, or 0
+}
+
+// String returns the modules as 1 for a bar and 0 for a space.
+func (s Symbol) String() string {
+	var b strings.Builder
+	for _, m := range s.Modules {
+		if m {
+			b.WriteByte('1')
+		} else {
+			b.WriteByte('0')
+		}
+	}
+	return b.String()
+}
+
+// Module patterns of the digits in the left-hand odd (L) and even (G)
+// sets and the right-hand (R) set.  R is the complement of L and G is
+// R reversed.
+var symbolSets = map[byte][10]string{
+	'L': {"0001101", "0011001", "0010011", "0111101", "0100011", "0110001", "0101111", "0111011", "0110111", "0001011"},
+	'G': {"0100111", "0110011", "0011011", "0100001", "0011101", "0111001", "0000101", "0010001", "0001001", "0010111"},
+	'R': {"1110010", "1100110", "1101100", "1000010", "1011100", "1001110", "1010000", "1000100", "1001000", "1110100"},
+}
+
+// ean13Parity is the set of each of the six left-hand digits of an
+// EAN-13, chosen by its leading digit, which isn't encoded otherwise.
+var ean13Parity = [10]string{
+	"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+	"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+}
+
+// upcEParity is the set of each digit of a number system 0 UPC-E,
+// chosen by its check digit.  Number system 1 swaps L and G.
+var upcEParity = [10]string{
+	"GGGLLL", "GGLGLL", "GGLLGL", "GGLLLG", "GLGGLL",
+	"GLLGGL", "GLLLGG", "GLGLGL", "GLGLLG", "GLLGLG",
+}
+
+// addOn2Parity is the set of each digit of an EAN-2 add-on, chosen by
+// its value modulo 4.
+var addOn2Parity = [4]string{"LL", "LG", "GL", "GG"}
+
+// addOn5Parity is the set of each digit of an EAN-5 add-on, chosen by
+// its checksum.
+var addOn5Parity = [10]string{
+	"GGLLL", "GLGLL", "GLLGL", "GLLLG", "LGGLL",
+	"LLGGL", "LLLGG", "LGLGL", "LGLLG", "LLGLG",
+}
+
+// Guard patterns.
+const (
+	normalGuard = "101"
+	centreGuard = "01010"
+	upcEGuard   = "010101"
+	addOnGuard  = "1011"
+	addOnSep    = "01"
+)
+
+// Quiet zones in modules.  An add-on is separated from the main symbol
+// by addOnGap modules, and followed by addOnQuiet.
+const (
+	addOnGap   = 9
+	addOnQuiet = 5
+)
+
+// symbolWriter accumulates the modules of a symbol.
+type symbolWriter struct {
+	s *Symbol
+}
+
+// pattern appends modules written as 1 for a bar and 0 for a space.
+func (w symbolWriter) pattern(p string, guard bool) {
+	for _, b := range []byte(p) {
+		w.s.Modules = append(w.s.Modules, b == '1')
+		w.s.Guards = append(w.s.Guards, guard && b == '1')
+	}
+}
+
+// digit appends a digit in a set: L, G or R.
+func (w symbolWriter) digit(d byte, set byte, guard bool) {
+	w.pattern(symbolSets[set][d-48], guard)
+}
+
+// quiet appends n modules of space.
+func (w symbolWriter) quiet(n int) {
+	w.pattern(strings.Repeat("0", n), false)
+}
+
+// newSymbol returns an empty symbol with its left quiet zone.
+func newSymbol(f SymbolFormat, digits string, quietLeft int) (*Symbol, symbolWriter) {
+	s := &Symbol{Format: f, Digits: digits, QuietLeft: quietLeft}
+	w := symbolWriter{s}
+	w.quiet(quietLeft)
+	return s, w
+}
+
+// Symbol returns the UPC-A barcode of the UPC.  The bars of the number
+// system and check digit are extended like the guards.
+func (u Upc) Symbol() Symbol {
+	digits := u.String()
+	s, w := newSymbol(SymbolUpcA, digits, 9)
+	w.pattern(normalGuard, true)
+	for i := 0; i < 6; i++ {
+		w.digit(digits[i], 'L', i == 0)
+	}
+	w.pattern(centreGuard, true)
+	for i := 6; i < 12; i++ {
+		w.digit(digits[i], 'R', i == 11)
+	}
+	w.pattern(normalGuard, true)
+	s.QuietRight = 9
+	w.quiet(s.QuietRight)
+	return *s
+}
+
+// Symbol returns the EAN-13 barcode of the EAN.  An EAN beginning with
+// 0 has the same bars as the UPC-A of its last 12 digits.
+func (e Ean) Symbol() Symbol {
+	digits := e.String()
+	s, w := newSymbol(SymbolEan13, digits, 11)
+	parity := ean13Parity[digits[0]-48]
+	w.pattern(normalGuard, true)
+	for i := 1; i < 7; i++ {
+		w.digit(digits[i], parity[i-1], false)
+	}
+	w.pattern(centreGuard, true)
+	for i := 7; i < 13; i++ {
+		w.digit(digits[i], 'R', false)
+	}
+	w.pattern(normalGuard, true)
+	s.QuietRight = 7
+	w.quiet(s.QuietRight)
+	return *s
+}
+
+// Symbol returns the UPC-E barcode of the UPC-E.  Its number system and
+// check digit aren't encoded as digits, but select the set of each of
+// its six digits.
+func (e UpcE) Symbol() Symbol {
+	digits := e.String()
+	s, w := newSymbol(SymbolUpcE, digits, 9)
+	parity := upcEParity[e.CheckDigit()]
+	w.pattern(normalGuard, true)
+	for i := 1; i < 7; i++ {
+		set := parity[i-1]
+		if e.NumberSystem() == 1 {
+			set = 'L' + 'G' - set
+		}
+		w.digit(digits[i], set, false)
+	}
+	w.pattern(upcEGuard, true)
+	s.QuietRight = 7
+	w.quiet(s.QuietRight)
+	return *s
+}
+
+// Symbol returns the EAN-8 barcode of the EAN-8.
+func (e Ean8) Symbol() Symbol {
+	digits := e.String()
+	s, w := newSymbol(SymbolEan8, digits, 7)
+	w.pattern(normalGuard, true)
+	for i := 0; i < 4; i++ {
+		w.digit(digits[i], 'L', false)
+	}
+	w.pattern(centreGuard, true)
+	for i := 4; i < 8; i++ {
+		w.digit(digits[i], 'R', false)
+	}
+	w.pattern(normalGuard, true)
+	s.QuietRight = 7
+	w.quiet(s.QuietRight)
+	return *s
+}
+
+// Symbol returns the UPC-A barcode of the UPC followed by the barcode
+// of its add-on, if any.
+func (u UpcWithAddOn) Symbol() Symbol {
+	return u.Upc.Symbol().withAddOn(u.AddOn)
+}
+
+// Symbol returns the EAN-13 barcode of the EAN followed by the barcode
+// of its add-on, if any.
+func (e EanWithAddOn) Symbol() Symbol {
+	return e.Ean.Symbol().withAddOn(e.AddOn)
+}
+
+// withAddOn appends the barcode of an add-on to the symbol.  The gap
+// between them replaces the right quiet zone of the main symbol.
+func (s Symbol) withAddOn(a AddOn) Symbol {
+	if a.Len() == 0 {
+		return s
+	}
+	s.Modules = s.Modules[:len(s.Modules)-s.QuietRight]
+	s.Guards = s.Guards[:len(s.Guards)-s.QuietRight]
+	s.AddOn = a.String()
+	w := symbolWriter{&s}
+	w.quiet(addOnGap)
+	s.AddOnStart = len(s.Modules)
+
+	var parity string
+	if a.Len() == 2 {
+		parity = addOn2Parity[a.Value()%4]
+	} else {
+		parity = addOn5Parity[addOn5Checksum(s.AddOn)]
+	}
+	w.pattern(addOnGuard, false)
+	for i := range s.AddOn {
+		if i > 0 {
+			w.pattern(addOnSep, false)
+		}
+		w.digit(s.AddOn[i], parity[i], false)
+	}
+	s.QuietRight = addOnQuiet
+	w.quiet(s.QuietRight)
+	return s
+}
+
+// addOn5Checksum returns the checksum of the digits of an EAN-5
+// add-on, which selects their sets.
+func addOn5Checksum(digits string) int {
+	sum := 0
+	for i, b := range []byte(digits) {
+		if i%2 == 0 {
+			sum += 3 * int(b-48)
+		} else {
+			sum += 9 * int(b-48)
+		}
+	}
+	return sum % 10
+}
@@ -0,0 +1,112 @@
+package upc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpcSymbol(t *testing.T) {
+	u, _ := Parse("036000291452")
+	s := u.Symbol()
+	want := strings.Repeat("0", 9) + "101" +
+		"0001101" + "0111101" + "0101111" + "0001101" + "0001101" + "0001101" +
+		"01010" +
+		"1101100" + "1110100" + "1100110" + "1011100" + "1001110" + "1101100" +
+		"101" + strings.Repeat("0", 9)
+	if s.String() != want {
+		t.Errorf("wrong modules\n got: %s\nwant: %s", s, want)
+	}
+	if s.Format != SymbolUpcA || s.Digits != "036000291452" || s.QuietLeft != 9 || s.QuietRight != 9 {
+		t.Errorf("wrong symbol: got %v %s %d %d", s.Format, s.Digits, s.QuietLeft, s.QuietRight)
+	}
+	if len(s.Guards) != len(s.Modules) || len(s.Modules) != 113 {
+		t.Errorf("wrong length: got %d modules, %d guards", len(s.Modules), len(s.Guards))
+	}
+
+	// guards and the outer digits are extended
+	var guards int
+	for i, g := range s.Guards {
+		if g && !s.Modules[i] {
+			t.Errorf("module %d: space marked as guard", i)
+		}
+		if g {
+			guards++
+		}
+	}
+	if want := 2 + 2 + 2 + 3 + 4; guards != want {
+		t.Errorf("wrong number of guard modules: got %d want %d", guards, want)
+	}
+
+	// an EAN beginning with 0 has the same bars
+	e, _ := ParseEan("0036000291452")
+	if es := e.Symbol(); es.String()[11:106] != s.String()[9:104] {
+		t.Errorf("EAN-13 bars differ from UPC-A\n got: %s\nwant: %s", es, s)
+	}
+}
+
+func TestEanSymbol(t *testing.T) {
+	e, _ := ParseEan("4006381333931")
+	want := strings.Repeat("0", 11) + "101" +
+		"0001101" + "0100111" + "0101111" + "0111101" + "0001001" + "0110011" +
+		"01010" +
+		"1000010" + "1000010" + "1000010" + "1110100" + "1000010" + "1100110" +
+		"101" + strings.Repeat("0", 7)
+	if s := e.Symbol(); s.String() != want || s.Format != SymbolEan13 {
+		t.Errorf("wrong modules\n got: %s\nwant: %s", s, want)
+	}
+
+	e8, _ := ParseEan8("96385074")
+	want = strings.Repeat("0", 7) + "101" +
+		"0001011" + "0101111" + "0111101" + "0110111" +
+		"01010" +
+		"1001110" + "1110010" + "1000100" + "1011100" +
+		"101" + strings.Repeat("0", 7)
+	if s := e8.Symbol(); s.String() != want || s.Format != SymbolEan8 {
+		t.Errorf("wrong modules\n got: %s\nwant: %s", s, want)
+	}
+}
+
+func TestUpcESymbol(t *testing.T) {
+	e, err := ParseUpcE("04252614")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Repeat("0", 9) + "101" +
+		"0011101" + "0010011" + "0111001" + "0011011" + "0101111" + "0011001" +
+		"010101" + strings.Repeat("0", 7)
+	if s := e.Symbol(); s.String() != want || s.Format != SymbolUpcE {
+		t.Errorf("wrong modules\n got: %s\nwant: %s", s, want)
+	}
+
+	// number system 1 swaps the sets
+	e, _ = ParseUpcE("14252611")
+	want = strings.Repeat("0", 9) + "101" +
+		"0100011" + "0010011" + "0111001" + "0010011" + "0000101" + "0110011" +
+		"010101" + strings.Repeat("0", 7)
+	if s := e.Symbol(); s.String() != want {
+		t.Errorf("wrong modules\n got: %s\nwant: %s", s, want)
+	}
+}
+
+func TestAddOnSymbol(t *testing.T) {
+	u, err := ParseWithAddOn("036000291452 52495")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := u.Symbol()
+	want := strings.Repeat("0", 9) + "1011" +
+		"0111001" + "01" + "0010011" + "01" + "0011101" + "01" + "0001011" + "01" + "0110001" +
+		strings.Repeat("0", 5)
+	if got := s.String()[104:]; got != want {
+		t.Errorf("wrong add-on modules\n got: %s\nwant: %s", got, want)
+	}
+	if s.AddOn != "52495" || s.AddOnStart != 113 || s.QuietRight != 5 {
+		t.Errorf("wrong add-on: got %s at %d", s.AddOn, s.AddOnStart)
+	}
+
+	e, _ := ParseEanWithAddOn("4006381333931 12")
+	want = "1011" + "0011001" + "01" + "0010011" + strings.Repeat("0", 5)
+	if got := e.Symbol().String()[106+9:]; got != want {
+		t.Errorf("wrong add-on modules\n got: %s\nwant: %s", got, want)
+	}
+}
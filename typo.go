@@ -0,0 +1,105 @@
+package upc
+
+import "sort"
+
+// SuggestUpc returns the valid UPCs within one typo of s, a 12-digit
+// UPC with an invalid check digit.  A typo is a single wrong digit or
+// two swapped adjacent digits, the errors a check digit detects.  The
+// check digit can't tell which digit is wrong, so there are usually
+// several candidates.  Those in known come first, then the rest in
+// numeric order.  known may be nil.
+//
+// If s is already valid, it's the only candidate.  Errors other than
+// ErrInvalidCheckDigit are returned as from Parse.
+func SuggestUpc(s string, known map[Upc]bool) ([]Upc, error) {
+	u, err := Parse(s)
+	if err == nil {
+		return []Upc{u}, nil
+	}
+	if err != ErrInvalidCheckDigit {
+		return nil, err
+	}
+
+	var candidates []Upc
+	for _, c := range typos(s) {
+		u, _ := Parse(c)
+		candidates = append(candidates, u)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if known[candidates[i]] != known[candidates[j]] {
+			return known[candidates[i]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates, nil
+}
+
+// SuggestEan returns the valid EANs within one typo of s, an EAN with
+// an invalid check digit.  It works like SuggestUpc, with errors as
+// from ParseEan.
+func SuggestEan(s string, known map[Ean]bool) ([]Ean, error) {
+	e, err := ParseEan(s)
+	if err == nil {
+		return []Ean{e}, nil
+	}
+	if err != ErrEanInvalidCheckDigit {
+		return nil, err
+	}
+
+	var candidates []Ean
+	for _, c := range typos(s) {
+		e, _ := ParseEan(c)
+		candidates = append(candidates, e)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if known[candidates[i]] != known[candidates[j]] {
+			return known[candidates[i]]
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates, nil
+}
+
+// typos returns the distinct strings of digits with a valid mod-10
+// check digit which differ from s by one digit or by one swap of
+// adjacent digits.
+func typos(s string) []string {
+	seen := make(map[string]bool)
+	var valid []string
+	try := func(b []byte) {
+		c := string(b)
+		if c != s && !seen[c] && checkSum(c) == 0 {
+			seen[c] = true
+			valid = append(valid, c)
+		}
+	}
+
+	b := []byte(s)
+	for i := range b {
+		orig := b[i]
+		for d := byte('0'); d <= '9'; d++ {
+			b[i] = d
+			try(b)
+		}
+		b[i] = orig
+	}
+	for i := 0; i+1 < len(b); i++ {
+		b[i], b[i+1] = b[i+1], b[i]
+		try(b)
+		b[i], b[i+1] = b[i+1], b[i]
+	}
+	return valid
+}
+
+// checkSum returns the mod-10 weighted sum of a string of digits which
+// ends with its check digit.  The check digit has weight 1, and the
+// weights alternate between 3 and 1 to its left, so the sum is 0 when
+// the check digit is valid.
+func checkSum(s string) int {
+	sum, weight := 0, 1
+	for i := len(s) - 1; i >= 0; i-- {
+		sum += weight * int(s[i]-48)
+		weight = 4 - weight
+	}
+	return sum % 10
+}
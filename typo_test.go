@@ -0,0 +1,66 @@
+package upc
+
+import "testing"
+
+func TestSuggestUpc(t *testing.T) {
+	want, _ := Parse("045496830434")
+	for _, s := range []string{
+		"045496830435", // wrong check digit
+		"045496880434", // wrong digit
+		"045496803434", // swapped digits
+	} {
+		candidates, err := SuggestUpc(s, nil)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if len(candidates) < 12 {
+			t.Errorf("%s: too few candidates: got %d", s, len(candidates))
+		}
+		found := false
+		for i, u := range candidates {
+			found = found || u == want
+			if _, err := Parse(u.String()); err != nil {
+				t.Errorf("%s: invalid candidate %s", s, u)
+			}
+			if i > 0 && candidates[i-1] >= u {
+				t.Errorf("%s: candidates out of order at %s", s, u)
+			}
+		}
+		if !found {
+			t.Errorf("%s: missing candidate %s", s, want)
+		}
+
+		candidates, _ = SuggestUpc(s, map[Upc]bool{want: true})
+		if candidates[0] != want {
+			t.Errorf("%s: known candidate not first: got %s", s, candidates[0])
+		}
+	}
+
+	candidates, err := SuggestUpc("045496830434", nil)
+	if err != nil || len(candidates) != 1 || candidates[0] != want {
+		t.Errorf("valid UPC: got %v, %v", candidates, err)
+	}
+	if _, err := SuggestUpc("04549683043", nil); err != ErrTooShort {
+		t.Errorf("expected ErrTooShort got %q", err)
+	}
+}
+
+func TestSuggestEan(t *testing.T) {
+	want, _ := ParseEan("4902370536485")
+	candidates, err := SuggestEan("4902307536485", map[Ean]bool{want: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if candidates[0] != want {
+		t.Errorf("known candidate not first: got %s", candidates[0])
+	}
+	for _, e := range candidates {
+		if _, err := ParseEan(e.String()); err != nil {
+			t.Errorf("invalid candidate %s", e)
+		}
+	}
+	if _, err := SuggestEan("49023705364851", nil); err != ErrEanTooLong {
+		t.Errorf("expected ErrEanTooLong got %q", err)
+	}
+}
@@ -12,18 +12,27 @@ type Upc int64
 var ErrTooShort = errors.New("UPC is too short (must be 12 digits)")
 var ErrTooLong = errors.New("UPC is too long (must be 12 digits)")
 var ErrInvalidCheckDigit = errors.New("UPC has an invalid check digit")
+var ErrAddOn = errors.New("UPC is followed by an add-on (use ParseWithAddOn)")
 
-// Parse parses a string into a Upc value.  The following errors can
-// be returned in addition to integer parsing errors:
+// Parse parses a string into a Upc value.  The string must be exactly
+// 12 digits, so a valid UPC followed by an add-on is rejected with
+// ErrAddOn; use ParseWithAddOn for those.  The following errors can be
+// returned in addition to integer parsing errors:
 //
 //     ErrTooShort
 //     ErrTooLong
+//     ErrAddOn
 //     ErrInvalidCheckDigit
 func Parse(s string) (Upc, error) {
 	if len(s) < 12 {
 		return 0, ErrTooShort
 	}
 	if len(s) > 12 {
+		if _, _, ok := splitAddOn(s, 12); ok {
+			if _, err := ParseWithAddOn(s); err == nil {
+				return 0, ErrAddOn
+			}
+		}
 		return 0, ErrTooLong
 	}
 
@@ -87,7 +96,8 @@ func (u Upc) IsGlobalProduct() bool {
 }
 
 // IsLocal returns true if the number system is 2 or 4.  These UPCs
-// are intended for local/warehouse use.
+// are intended for local/warehouse use.  See VariableMeasure for
+// decoding the price or weight embedded in number system 2.
 func (u Upc) IsLocal() bool {
 	switch u.NumberSystem() {
 	case 2, 4:
@@ -105,7 +115,8 @@ func (u Upc) IsDrug() bool {
 }
 
 // IsCoupon returns true if the number system is 5.  These UPCs are
-// intended for labeling coupons.  See Family and Value methods.
+// intended for labeling coupons.  See Family and Value methods, and
+// ParseCoupon for the GS1 DataBar coupons which replaced them.
 func (u Upc) IsCoupon() bool {
 	return u.NumberSystem() == 5
 }
@@ -114,6 +125,10 @@ func (u Upc) IsCoupon() bool {
 // GS1 organization.  The return value is a string because leading
 // zeros are used for lookups in the standard GS1 databases. In the
 // case of coupons, the manufacturer is only 5 digits long.
+//
+// Company prefixes actually range from 6 to 11 digits, so the 6-digit
+// split is only right for the oldest UPCs.  Use CompanyPrefix with a
+// GcpTable to find the real one.
 func (u Upc) Manufacturer() string {
 	if u.NumberSystem() == 5 {
 		return fmt.Sprintf("%05d", (u/100000)%100000)
@@ -122,7 +137,8 @@ func (u Upc) Manufacturer() string {
 	}
 }
 
-// Product returns the product code assigned by a manufacturer.
+// Product returns the product code assigned by a manufacturer,
+// assuming a 6-digit manufacturer code.  See ItemReference.
 func (u Upc) Product() int {
 	return int(u % 100000)
 }
@@ -132,7 +148,7 @@ func (u Upc) Product() int {
 //
 // The value is a string because leading zeros are meaningful in the
 // FDA database of labeler codes.  No attempt is made to put the NDC
-// code into standard format with dashes.
+// code into standard format with dashes.  See NdcSegments.
 func (u Upc) Ndc() string {
 	full := fmt.Sprintf("%011d", u)
 	return full[1:]
@@ -0,0 +1,138 @@
+package upc
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UpcE represents a zero-suppressed UPC-E code.  It's stored as a
+// 64-bit integer holding the number system followed by the six
+// encoded digits, without the check digit.  The check digit of a UPC-E
+// is the check digit of the UPC-A it expands to.
+type UpcE int64
+
+var ErrUpcETooShort = errors.New("UPC-E is too short (must be 6, 7 or 8 digits)")
+var ErrUpcETooLong = errors.New("UPC-E is too long (must be 6, 7 or 8 digits)")
+var ErrUpcEInvalidCheckDigit = errors.New("UPC-E has an invalid check digit")
+var ErrUpcENumberSystem = errors.New("UPC-E number system must be 0 or 1")
+
+// ParseUpcE parses a string into a UpcE value.  The string may have
+// 6 digits (the encoded digits alone, number system 0), 7 digits (the
+// number system followed by the encoded digits) or 8 digits (number
+// system, encoded digits and check digit).  The following errors can
+// be returned in addition to integer parsing errors:
+//
+//	ErrUpcETooShort
+//	ErrUpcETooLong
+//	ErrUpcEInvalidCheckDigit
+//	ErrUpcENumberSystem
+func ParseUpcE(s string) (UpcE, error) {
+	if len(s) < 6 {
+		return 0, ErrUpcETooShort
+	}
+	if len(s) > 8 {
+		return 0, ErrUpcETooLong
+	}
+
+	var n int64
+	check := -1
+	for i, b := range []byte(s) {
+		if b < 48 || b > 57 {
+			return 0, fmt.Errorf("Invalid UPC-E digit: %c", b)
+		}
+		if i == 7 {
+			check = int(b - 48)
+		} else {
+			n *= 10
+			n += int64(b - 48)
+		}
+	}
+	e := UpcE(n)
+	if ns := e.NumberSystem(); ns != 0 && ns != 1 {
+		return 0, ErrUpcENumberSystem
+	}
+	if check >= 0 && e.CheckDigit() != check {
+		return 0, ErrUpcEInvalidCheckDigit
+	}
+
+	return e, nil
+}
+
+// String returns the standard, 8-digit string representation of this
+// UPC-E, including number system and check digit.
+func (e UpcE) String() string {
+	return fmt.Sprintf("%07d%d", int64(e), e.CheckDigit())
+}
+
+// NumberSystem returns the first digit of the UPC-E.  It's always 0
+// or 1.
+func (e UpcE) NumberSystem() int {
+	return int(e / 1000000)
+}
+
+// CheckDigit returns the check digit that should be used as the 8th
+// digit of the UPC-E.  It's the same as the check digit of the
+// expanded UPC-A.
+func (e UpcE) CheckDigit() int {
+	return e.Upc().CheckDigit()
+}
+
+// Upc expands the UPC-E into its equivalent UPC-A.  The last encoded
+// digit determines how the suppressed zeros are restored:
+//
+//	0, 1, 2:  manufacturer XXd00, product 00YYY
+//	3:        manufacturer XXX00, product 000YY
+//	4:        manufacturer XXXX0, product 0000Y
+//	5-9:      manufacturer XXXXX, product 0000d
+func (e UpcE) Upc() Upc {
+	d := make([]int64, 6)
+	n := int64(e)
+	for i := 5; i >= 0; i-- {
+		d[i] = n % 10
+		n /= 10
+	}
+
+	var manufacturer, product int64
+	switch d[5] {
+	case 0, 1, 2:
+		manufacturer = d[0]*10000 + d[1]*1000 + d[5]*100
+		product = d[2]*100 + d[3]*10 + d[4]
+	case 3:
+		manufacturer = d[0]*10000 + d[1]*1000 + d[2]*100
+		product = d[3]*10 + d[4]
+	case 4:
+		manufacturer = d[0]*10000 + d[1]*1000 + d[2]*100 + d[3]*10
+		product = d[4]
+	default:
+		manufacturer = d[0]*10000 + d[1]*1000 + d[2]*100 + d[3]*10 + d[4]
+		product = d[5]
+	}
+	return Upc(int64(e.NumberSystem())*10000000000 + manufacturer*100000 + product)
+}
+
+// UpcE returns the zero-suppressed form of this UPC.  The second
+// return value is false if the UPC has no UPC-E equivalent.  Only
+// number systems 0 and 1 can be zero-suppressed.
+func (u Upc) UpcE() (UpcE, bool) {
+	ns := int64(u.NumberSystem())
+	if ns != 0 && ns != 1 {
+		return 0, false
+	}
+	manufacturer := int64(u/100000) % 100000
+	product := int64(u % 100000)
+
+	var body int64
+	switch {
+	case manufacturer%1000 <= 200 && manufacturer%100 == 0 && product <= 999:
+		body = (manufacturer/1000)*10000 + product*10 + (manufacturer%1000)/100
+	case manufacturer%100 == 0 && product <= 99:
+		body = (manufacturer/100)*1000 + product*10 + 3
+	case manufacturer%10 == 0 && product <= 9:
+		body = (manufacturer/10)*100 + product*10 + 4
+	case product >= 5 && product <= 9:
+		body = manufacturer*10 + product
+	default:
+		return 0, false
+	}
+	return UpcE(ns*1000000 + body), true
+}
@@ -0,0 +1,71 @@
+package upc
+
+import "testing"
+
+var upcETests = map[string]string{
+	"04252614": "042100005264",
+	"01234505": "012000003455",
+	"01234531": "012300000451",
+	"01234446": "012340000046",
+	"01234565": "012345000065",
+	"11234562": "112345000062",
+}
+
+func TestUpcE(t *testing.T) {
+	for s, a := range upcETests {
+		e, err := ParseUpcE(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		if e.String() != s {
+			t.Errorf("%s: wrong string: got %s", s, e)
+		}
+		u := e.Upc()
+		if u.String() != a {
+			t.Errorf("%s: wrong expansion: got %s want %s", s, u, a)
+		}
+		back, ok := u.UpcE()
+		if !ok {
+			t.Errorf("%s: %s has no UPC-E", s, u)
+		} else if back != e {
+			t.Errorf("%s: wrong compression: got %s", s, back)
+		}
+	}
+}
+
+func TestUpcEShortForms(t *testing.T) {
+	for _, s := range []string{"425261", "0425261", "04252614"} {
+		e, err := ParseUpcE(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if e.String() != "04252614" {
+			t.Errorf("%s: wrong string: got %s", s, e)
+		}
+	}
+}
+
+func TestUpcEWrong(t *testing.T) {
+	errs := map[string]error{
+		"":          ErrUpcETooShort,
+		"12345":     ErrUpcETooShort,
+		"042526140": ErrUpcETooLong,
+		"04252615":  ErrUpcEInvalidCheckDigit,
+		"24252614":  ErrUpcENumberSystem,
+	}
+	for s, want := range errs {
+		if _, err := ParseUpcE(s); err != want {
+			t.Errorf("%s: expected %q got %q", s, want, err)
+		}
+	}
+	if _, err := ParseUpcE("0425x614"); err == nil {
+		t.Errorf("expected an error got none")
+	}
+
+	for _, s := range []string{"045496830434", "298765432109", "012345100000"} {
+		u, _ := Parse(s)
+		if e, ok := u.UpcE(); ok {
+			t.Errorf("%s: unexpected UPC-E %s", s, e)
+		}
+	}
+}
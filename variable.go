@@ -0,0 +1,232 @@
+package upc
+
+import (
+	"errors"
+	"math"
+)
+
+// Measure is the kind of value embedded in a variable measure code.
+type Measure int
+
+const (
+	MeasurePrice Measure = iota
+	MeasureWeight
+)
+
+// VariableLayout describes how a retailer lays out the 10 digits of a
+// variable measure code which follow the number system 2 of a UPC or
+// the 2-digit prefix (02 or 20-29) of an EAN.  The item reference comes
+// first, then an optional price check digit, then the price or weight.
+// The lengths must add up to 10.  A price check digit requires a 4- or
+// 5-digit value.
+type VariableLayout struct {
+	ItemDigits  int
+	PriceCheck  bool
+	ValueDigits int
+	Measure     Measure
+	Decimals    int // implied decimal places of the value
+}
+
+// Common variable measure layouts.  Retailers choose their own, so
+// these are only starting points.
+var (
+	// 2 IIIII V PPPP C, the usual layout in the United States
+	UpcPriceCheck4 = VariableLayout{ItemDigits: 5, PriceCheck: true, ValueDigits: 4, Measure: MeasurePrice, Decimals: 2}
+
+	// 2 IIIII PPPPP C
+	UpcPrice5 = VariableLayout{ItemDigits: 5, ValueDigits: 5, Measure: MeasurePrice, Decimals: 2}
+
+	// 2X IIIII PPPPP C
+	EanPrice5 = VariableLayout{ItemDigits: 5, ValueDigits: 5, Measure: MeasurePrice, Decimals: 2}
+
+	// 2X IIII V PPPPP C
+	EanPriceCheck5 = VariableLayout{ItemDigits: 4, PriceCheck: true, ValueDigits: 5, Measure: MeasurePrice, Decimals: 2}
+
+	// 2X IIIII WWWWW C, weight in kilograms to the gram
+	EanWeight5 = VariableLayout{ItemDigits: 5, ValueDigits: 5, Measure: MeasureWeight, Decimals: 3}
+)
+
+var ErrNotVariableMeasure = errors.New("not a variable measure code (must be number system 2 or EAN prefix 02 or 20-29)")
+var ErrInvalidLayout = errors.New("variable measure layout must have 10 digits, with a 4- or 5-digit value if it has a price check digit")
+var ErrInvalidPriceCheckDigit = errors.New("variable measure code has an invalid price check digit")
+var ErrVariablePrefix = errors.New("variable measure EAN prefix must be 02 or 20-29")
+var ErrItemOverflow = errors.New("item reference doesn't fit in the variable measure layout")
+var ErrValueOverflow = errors.New("price or weight doesn't fit in the variable measure layout")
+
+// VariableMeasure is the decoded content of a variable measure code,
+// also known as a random weight code.
+type VariableMeasure struct {
+	Prefix     int // 2 for a UPC, or the 2-digit EAN prefix
+	Item       int // item reference assigned by the retailer
+	PriceCheck int // -1 if the layout has no price check digit
+	Value      int // price or weight, without the decimal point
+	Layout     VariableLayout
+}
+
+// Amount returns the price or weight with the layout's decimal places
+// applied.
+func (v VariableMeasure) Amount() float64 {
+	return float64(v.Value) / math.Pow10(v.Layout.Decimals)
+}
+
+// VariableMeasure decodes a number system 2 UPC according to a layout.
+// The following errors can be returned:
+//
+//	ErrNotVariableMeasure
+//	ErrInvalidLayout
+//	ErrInvalidPriceCheckDigit
+func (u Upc) VariableMeasure(l VariableLayout) (VariableMeasure, error) {
+	if u.NumberSystem() != 2 {
+		return VariableMeasure{}, ErrNotVariableMeasure
+	}
+	return decodeVariableMeasure(2, int64(u)%10000000000, l)
+}
+
+// VariableMeasure decodes an EAN with prefix 02 or 20-29 according to
+// a layout.  The following errors can be returned:
+//
+//	ErrNotVariableMeasure
+//	ErrInvalidLayout
+//	ErrInvalidPriceCheckDigit
+func (e Ean) VariableMeasure(l VariableLayout) (VariableMeasure, error) {
+	prefix := int(e / 10000000000)
+	if prefix != 2 && (prefix < 20 || prefix > 29) {
+		return VariableMeasure{}, ErrNotVariableMeasure
+	}
+	return decodeVariableMeasure(prefix, int64(e)%10000000000, l)
+}
+
+// decodeVariableMeasure splits the 10 digits following the prefix of
+// a variable measure code according to a layout.
+func decodeVariableMeasure(prefix int, n int64, l VariableLayout) (VariableMeasure, error) {
+	if !l.valid() {
+		return VariableMeasure{}, ErrInvalidLayout
+	}
+	v := VariableMeasure{Prefix: prefix, PriceCheck: -1, Layout: l}
+	v.Value = int(n % pow10(l.ValueDigits))
+	n /= pow10(l.ValueDigits)
+	if l.PriceCheck {
+		v.PriceCheck = int(n % 10)
+		n /= 10
+		if priceCheckDigit(v.Value, l.ValueDigits) != v.PriceCheck {
+			return VariableMeasure{}, ErrInvalidPriceCheckDigit
+		}
+	}
+	v.Item = int(n)
+	return v, nil
+}
+
+// valid returns true if the layout describes exactly 10 digits.
+func (l VariableLayout) valid() bool {
+	if l.ItemDigits < 0 || l.ValueDigits < 1 || l.Decimals < 0 {
+		return false
+	}
+	n := l.ItemDigits + l.ValueDigits
+	if l.PriceCheck {
+		if l.ValueDigits != 4 && l.ValueDigits != 5 {
+			return false
+		}
+		n++
+	}
+	return n == 10
+}
+
+// pow10 returns 10 to the power of n.
+func pow10(n int) int64 {
+	p := int64(1)
+	for ; n > 0; n-- {
+		p *= 10
+	}
+	return p
+}
+
+// Weighting factors of the price check digit algorithms, indexed by
+// digit.  The names follow the GS1 General Specifications: "2-" is the
+// product with 2 minus its tens digit, "5+" the product with 5 plus
+// its tens digit and so on, keeping only the units digit.
+var (
+	weight2Minus = [10]int{0, 2, 4, 6, 8, 9, 1, 3, 5, 7}
+	weight3      = [10]int{0, 3, 6, 9, 2, 5, 8, 1, 4, 7}
+	weight5Plus  = [10]int{0, 5, 1, 6, 2, 7, 3, 8, 4, 9}
+	weight5Minus = [10]int{0, 5, 9, 4, 8, 3, 7, 2, 6, 1}
+)
+
+// priceCheckDigit returns the price check digit of a 4- or 5-digit
+// price, or -1 for any other length.
+func priceCheckDigit(value, digits int) int {
+	d := make([]int, digits)
+	for i := digits - 1; i >= 0; i-- {
+		d[i] = value % 10
+		value /= 10
+	}
+
+	switch digits {
+	case 4:
+		sum := weight2Minus[d[0]] + weight2Minus[d[1]] + weight3[d[2]] + weight5Minus[d[3]]
+		return (sum * 3) % 10
+	case 5:
+		sum := weight5Plus[d[0]] + weight2Minus[d[1]] + weight5Minus[d[2]] + weight5Plus[d[3]] + weight2Minus[d[4]]
+		target := (10 - sum%10) % 10
+		for c, w := range weight5Minus {
+			if w == target {
+				return c
+			}
+		}
+	}
+	return -1
+}
+
+// NewVariableUpc builds a number system 2 UPC for printing on in-store
+// labels.  The price check digit is computed when the layout has one.
+// The following errors can be returned:
+//
+//	ErrInvalidLayout
+//	ErrItemOverflow
+//	ErrValueOverflow
+func NewVariableUpc(l VariableLayout, item, value int) (Upc, error) {
+	n, err := encodeVariableMeasure(l, item, value)
+	if err != nil {
+		return 0, err
+	}
+	return Upc(20000000000 + n), nil
+}
+
+// NewVariableEan builds an EAN with prefix 02 or 20-29 for printing on
+// in-store labels.  Prefix 02 is given as 2.  The price check digit is
+// computed when the layout has one.  The following errors can be
+// returned:
+//
+//	ErrVariablePrefix
+//	ErrInvalidLayout
+//	ErrItemOverflow
+//	ErrValueOverflow
+func NewVariableEan(prefix int, l VariableLayout, item, value int) (Ean, error) {
+	if prefix != 2 && (prefix < 20 || prefix > 29) {
+		return 0, ErrVariablePrefix
+	}
+	n, err := encodeVariableMeasure(l, item, value)
+	if err != nil {
+		return 0, err
+	}
+	return Ean(int64(prefix)*10000000000 + n), nil
+}
+
+// encodeVariableMeasure returns the 10 digits following the prefix of
+// a variable measure code.
+func encodeVariableMeasure(l VariableLayout, item, value int) (int64, error) {
+	if !l.valid() {
+		return 0, ErrInvalidLayout
+	}
+	if item < 0 || int64(item) >= pow10(l.ItemDigits) {
+		return 0, ErrItemOverflow
+	}
+	if value < 0 || int64(value) >= pow10(l.ValueDigits) {
+		return 0, ErrValueOverflow
+	}
+
+	n := int64(item)
+	if l.PriceCheck {
+		n = n*10 + int64(priceCheckDigit(value, l.ValueDigits))
+	}
+	return n*pow10(l.ValueDigits) + int64(value), nil
+}
@@ -0,0 +1,185 @@
+package upc
+
+import "testing"
+
+func TestPriceCheckDigit(t *testing.T) {
+	tests := []struct{ value, digits, check int }{
+		{2875, 4, 9},
+		{14685, 5, 6},
+		{0, 4, 0},
+		{0, 5, 0},
+		{123, 3, -1},
+	}
+	for _, test := range tests {
+		if got := priceCheckDigit(test.value, test.digits); got != test.check {
+			t.Errorf("%0*d: got check digit %d want %d", test.digits, test.value, got, test.check)
+		}
+	}
+}
+
+func TestUpcVariableMeasure(t *testing.T) {
+	tests := map[string]struct {
+		layout VariableLayout
+		want   VariableMeasure
+		amount float64
+	}{
+		"212345928752": {
+			UpcPriceCheck4,
+			VariableMeasure{Prefix: 2, Item: 12345, PriceCheck: 9, Value: 2875, Layout: UpcPriceCheck4},
+			28.75,
+		},
+		"212345012994": {
+			UpcPrice5,
+			VariableMeasure{Prefix: 2, Item: 12345, PriceCheck: -1, Value: 1299, Layout: UpcPrice5},
+			12.99,
+		},
+	}
+	for s, test := range tests {
+		u, err := Parse(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		v, err := u.VariableMeasure(test.layout)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if v != test.want {
+			t.Errorf("%s: wrong measure\n got: %#v\nwant: %#v\n", s, v, test.want)
+		} else if v.Amount() != test.amount {
+			t.Errorf("%s: wrong amount: got %v want %v", s, v.Amount(), test.amount)
+		}
+	}
+}
+
+func TestEanVariableMeasure(t *testing.T) {
+	tests := map[string]struct {
+		layout VariableLayout
+		want   VariableMeasure
+		amount float64
+	}{
+		"2112346146859": {
+			EanPriceCheck5,
+			VariableMeasure{Prefix: 21, Item: 1234, PriceCheck: 6, Value: 14685, Layout: EanPriceCheck5},
+			146.85,
+		},
+		"2812345012505": {
+			EanWeight5,
+			VariableMeasure{Prefix: 28, Item: 12345, PriceCheck: -1, Value: 1250, Layout: EanWeight5},
+			1.25,
+		},
+		"0212345928752": {
+			UpcPriceCheck4,
+			VariableMeasure{Prefix: 2, Item: 12345, PriceCheck: 9, Value: 2875, Layout: UpcPriceCheck4},
+			28.75,
+		},
+	}
+	for s, test := range tests {
+		e, err := ParseEan(s)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+			continue
+		}
+		v, err := e.VariableMeasure(test.layout)
+		if err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if v != test.want {
+			t.Errorf("%s: wrong measure\n got: %#v\nwant: %#v\n", s, v, test.want)
+		} else if v.Amount() != test.amount {
+			t.Errorf("%s: wrong amount: got %v want %v", s, v.Amount(), test.amount)
+		}
+	}
+}
+
+func TestVariableMeasureWrong(t *testing.T) {
+	u, _ := Parse("045496830434")
+	if _, err := u.VariableMeasure(UpcPriceCheck4); err != ErrNotVariableMeasure {
+		t.Errorf("%s: expected ErrNotVariableMeasure got %q", u, err)
+	}
+	e, _ := ParseEan("4549673590600")
+	if _, err := e.VariableMeasure(EanPrice5); err != ErrNotVariableMeasure {
+		t.Errorf("%s: expected ErrNotVariableMeasure got %q", e, err)
+	}
+
+	u, _ = Parse("212345128756")
+	if _, err := u.VariableMeasure(UpcPriceCheck4); err != ErrInvalidPriceCheckDigit {
+		t.Errorf("%s: expected ErrInvalidPriceCheckDigit got %q", u, err)
+	}
+
+	layouts := []VariableLayout{
+		{ItemDigits: 5, ValueDigits: 4},
+		{ItemDigits: 6, PriceCheck: true, ValueDigits: 3},
+		{ItemDigits: 4, ValueDigits: 7},
+		{ItemDigits: 5, ValueDigits: 5, Decimals: -1},
+	}
+	for _, l := range layouts {
+		if _, err := u.VariableMeasure(l); err != ErrInvalidLayout {
+			t.Errorf("%#v: expected ErrInvalidLayout got %q", l, err)
+		}
+	}
+}
+
+func TestNewVariableUpc(t *testing.T) {
+	u, err := NewVariableUpc(UpcPriceCheck4, 12345, 2875)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.String() != "212345928752" {
+		t.Errorf("wrong UPC: got %s", u)
+	}
+	v, err := u.VariableMeasure(UpcPriceCheck4)
+	if err != nil || v.Item != 12345 || v.Value != 2875 {
+		t.Errorf("%s: wrong round trip: got %#v, %v", u, v, err)
+	}
+
+	if _, err := NewVariableUpc(UpcPriceCheck4, 123456, 2875); err != ErrItemOverflow {
+		t.Errorf("expected ErrItemOverflow got %q", err)
+	}
+	if _, err := NewVariableUpc(UpcPriceCheck4, -1, 2875); err != ErrItemOverflow {
+		t.Errorf("expected ErrItemOverflow got %q", err)
+	}
+	if _, err := NewVariableUpc(UpcPriceCheck4, 12345, 10000); err != ErrValueOverflow {
+		t.Errorf("expected ErrValueOverflow got %q", err)
+	}
+	if _, err := NewVariableUpc(VariableLayout{ItemDigits: 5, ValueDigits: 4}, 1, 1); err != ErrInvalidLayout {
+		t.Errorf("expected ErrInvalidLayout got %q", err)
+	}
+}
+
+func TestNewVariableEan(t *testing.T) {
+	tests := []struct {
+		prefix      int
+		layout      VariableLayout
+		item, value int
+		want        string
+	}{
+		{21, EanPriceCheck5, 1234, 14685, "2112346146859"},
+		{28, EanWeight5, 12345, 1250, "2812345012505"},
+		{2, UpcPriceCheck4, 12345, 2875, "0212345928752"},
+	}
+	for _, test := range tests {
+		e, err := NewVariableEan(test.prefix, test.layout, test.item, test.value)
+		if err != nil {
+			t.Errorf("%s: %s", test.want, err)
+			continue
+		}
+		if e.String() != test.want {
+			t.Errorf("%s: wrong EAN: got %s", test.want, e)
+		}
+		v, err := e.VariableMeasure(test.layout)
+		if err != nil || v.Prefix != test.prefix || v.Item != test.item || v.Value != test.value {
+			t.Errorf("%s: wrong round trip: got %#v, %v", e, v, err)
+		}
+	}
+
+	for _, prefix := range []int{0, 1, 19, 30} {
+		if _, err := NewVariableEan(prefix, EanPrice5, 1, 1); err != ErrVariablePrefix {
+			t.Errorf("%d: expected ErrVariablePrefix got %q", prefix, err)
+		}
+	}
+	if _, err := NewVariableEan(20, EanPriceCheck5, 10000, 1); err != ErrItemOverflow {
+		t.Errorf("expected ErrItemOverflow got %q", err)
+	}
+	if _, err := NewVariableEan(20, EanPrice5, 1, 100000); err != ErrValueOverflow {
+		t.Errorf("expected ErrValueOverflow got %q", err)
+	}
+}